@@ -0,0 +1,77 @@
+package cmds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	currencycmds "github.com/ProtoconNet/mitum-currency/v3/cmds"
+	"github.com/ProtoconNet/mitum-minic/digest"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	PNameDigestModuleDesign                        = ps.Name("digest-module-design")
+	ContextValueDigestModuleDesign util.ContextKey = "digest-module-design"
+)
+
+// PLoadDigestModuleDesign loads the digest section of the design file into
+// digest.Design, next to the digest design of currency. Like the digest
+// design of currency, only the local design file is supported.
+func PLoadDigestModuleDesign(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	var flag launch.DesignFlag
+
+	if err := util.LoadFromContextOK(ctx,
+		launch.LoggingContextKey, &log,
+		launch.DesignFlagContextKey, &flag,
+	); err != nil {
+		return ctx, err
+	}
+
+	var design digest.Design
+
+	switch flag.Scheme() {
+	case "file":
+		b, err := os.ReadFile(filepath.Clean(flag.URL().Path))
+		if err != nil {
+			return ctx, errors.WithStack(err)
+		}
+
+		var m struct {
+			Digest *digest.Design `yaml:"digest"`
+		}
+
+		if err := yaml.Unmarshal(b, &m); err != nil {
+			return ctx, errors.Wrap(err, "digest design")
+		}
+
+		if m.Digest != nil {
+			design = *m.Digest
+		}
+	default:
+		var cdesign currencycmds.DigestDesign
+
+		switch err := util.LoadFromContext(ctx, currencycmds.ContextValueDigestDesign, &cdesign); {
+		case err != nil && !errors.Is(err, util.ErrNotFound):
+			return ctx, err
+		case err == nil && !cdesign.Equal(currencycmds.DigestDesign{}):
+			return ctx, errors.Errorf("digest design of %q scheme is not supported", flag.Scheme())
+		}
+	}
+
+	if err := design.IsValid(); err != nil {
+		return ctx, errors.WithMessage(err, "digest design")
+	}
+
+	if len(design.CursorSecret) < 1 {
+		log.Log().Warn().Msg("cursor_secret of digest design is empty; cursors become invalid after restart")
+	}
+
+	return context.WithValue(ctx, ContextValueDigestModuleDesign, design), nil
+}
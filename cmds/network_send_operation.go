@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
@@ -14,6 +15,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 type NetworkClientCommand struct { //nolint:govet //...
@@ -52,21 +54,12 @@ func (cmd *BaseNetworkClientCommand) Prepare(pctx context.Context) error {
 		cmd.Timeout = isaac.DefaultTimeoutRequest * 2
 	}
 
-	connectionPool, err := launch.NewConnectionPool(
-		1<<9, //nolint:gomnd //...
-		base.NetworkID(cmd.NetworkID),
-		nil,
-	)
+	client, err := newBaseNetworkClient(cmd.Encoders, cmd.Encoder, base.NetworkID(cmd.NetworkID), cmd.ClientID)
 	if err != nil {
 		return err
 	}
 
-	cmd.Client = isaacnetwork.NewBaseClient(
-		cmd.Encoders, cmd.Encoder,
-		connectionPool.Dial,
-		connectionPool.CloseAll,
-	)
-	cmd.Client.SetClientID(cmd.ClientID)
+	cmd.Client = client
 
 	cmd.Log.Debug().
 		Stringer("remote", cmd.Remote).
@@ -78,6 +71,58 @@ func (cmd *BaseNetworkClientCommand) Prepare(pctx context.Context) error {
 	return nil
 }
 
+func newBaseNetworkClient(
+	encs *encoder.Encoders,
+	enc encoder.Encoder,
+	networkID base.NetworkID,
+	clientID string,
+) (*isaacnetwork.BaseClient, error) {
+	connectionPool, err := launch.NewConnectionPool(
+		1<<9, //nolint:gomnd //...
+		networkID,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := isaacnetwork.NewBaseClient(
+		encs, enc,
+		connectionPool.Dial,
+		connectionPool.CloseAll,
+	)
+	client.SetClientID(clientID)
+
+	return client, nil
+}
+
+// sendOperation sends op to the remote node; a rejected operation is logged,
+// not returned as error.
+func sendOperation(
+	pctx context.Context,
+	client *isaacnetwork.BaseClient,
+	remote launch.ConnInfoFlag,
+	timeout time.Duration,
+	op base.Operation,
+	log *zerolog.Logger,
+) error {
+	ctx, cancel := context.WithTimeout(pctx, timeout)
+	defer cancel()
+
+	switch sent, err := client.SendOperation(ctx, remote.ConnInfo(), op); {
+	case err != nil:
+		log.Error().Err(err).Stringer("operation", op.Hash()).Msg("not sent")
+
+		return err
+	case !sent:
+		log.Error().Stringer("operation", op.Hash()).Msg("not sent")
+	default:
+		log.Info().Stringer("operation", op.Hash()).Msg("sent")
+	}
+
+	return nil
+}
+
 func (cmd *BaseNetworkClientCommand) Print(v interface{}, out io.Writer) error {
 	l := cmd.Log.Debug().
 		Str("type", fmt.Sprintf("%T", v))
@@ -130,19 +175,5 @@ func (cmd *NetworkClientSendOperationCommand) Run(pctx context.Context) error {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
-	defer cancel()
-
-	switch sent, err := cmd.Client.SendOperation(ctx, cmd.Remote.ConnInfo(), op); {
-	case err != nil:
-		cmd.Log.Error().Err(err).Msg("not sent")
-
-		return err
-	case !sent:
-		cmd.Log.Error().Msg("not sent")
-	default:
-		cmd.Log.Info().Msg("sent")
-	}
-
-	return nil
+	return sendOperation(pctx, cmd.Client, cmd.Remote, cmd.Timeout, op, cmd.Log)
 }
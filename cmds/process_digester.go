@@ -29,6 +29,14 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
+	if err := digest.CreateIndexes(ctx, st); err != nil {
+		return ctx, err
+	}
+
+	if err := digest.Backfill(ctx, st); err != nil {
+		return ctx, err
+	}
+
 	var design launch.NodeDesign
 	if err := util.LoadFromContext(ctx, launch.DesignContextKey, &design); err != nil {
 		return ctx, err
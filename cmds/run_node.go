@@ -101,6 +101,7 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 	_ = pps.POK(launch.PNameEncoder).
 		PostAddOK(launch.PNameAddHinters, PAddHinters)
 	_ = pps.POK(currencycmds.PNameDigest).
+		PreAddOK(PNameDigestModuleDesign, PLoadDigestModuleDesign).
 		PostAddOK(currencycmds.PNameDigestAPIHandlers, cmd.pDigestAPIHandlers)
 	_ = pps.POK(currencycmds.PNameDigester).
 		PostAddOK(currencycmds.PNameDigesterFollowUp, PDigesterFollowUp)
@@ -496,7 +497,21 @@ func (cmd *RunCommand) setDigestHandlers(
 		return nil, err
 	}
 
-	handlers := digest.NewHandlers(ctx, params.ISAAC.NetworkID(), encs, enc, st, cache, router, routes)
+	var design digest.Design
+	if err := util.LoadFromContextOK(ctx, ContextValueDigestModuleDesign, &design); err != nil {
+		return nil, err
+	}
+
+	handlers := digest.NewHandlers(ctx, params.ISAAC.NetworkID(), encs, enc, st, cache, router, routes).
+		SetCursorSecret([]byte(design.CursorSecret))
+
+	if design.RateLimit != nil {
+		if err := handlers.SetRateLimit(*design.RateLimit); err != nil {
+			return nil, err
+		}
+
+		cmd.log.Debug().Interface("rate_limit", design.RateLimit).Msg("rate limit attached")
+	}
 
 	return handlers, nil
 }
@@ -0,0 +1,338 @@
+package cmds
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	currencycmds "github.com/ProtoconNet/mitum-currency/v3/cmds"
+	timestampcmds "github.com/ProtoconNet/mitum-timestamp/cmds"
+	"github.com/ProtoconNet/mitum-timestamp/operation/timestamp"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var fileHashers = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// RegisterFileHasher adds a hash function which can be selected by name with
+// the --hasher flag of the timestamp commands.
+func RegisterFileHasher(name string, f func() hash.Hash) {
+	fileHashers[strings.ToLower(name)] = f
+}
+
+func fileHasherNames() []string {
+	names := make([]string, 0, len(fileHashers))
+	for name := range fileHashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// HashFile returns the hex encoded digest of the file with the named hasher.
+func HashFile(path, hasher string) (string, error) {
+	f, found := fileHashers[strings.ToLower(hasher)]
+	if !found {
+		return "", errors.Errorf("unknown hasher, %q; available: %v", hasher, fileHasherNames())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	h := f()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// TimestampCommand extends the timestamp operation commands of
+// mitum-timestamp with the file commands.
+type TimestampCommand struct { //nolint:govet //...
+	timestampcmds.TimestampCommand `embed:""`
+	Stamp                          TimestampStampCommand  `cmd:"" name:"stamp" help:"hash files and create timestamp append operations"`
+	Verify                         TimestampVerifyCommand `cmd:"" name:"verify" help:"hash file and look up its timestamp in digest api"`
+}
+
+type TimestampStampCommand struct { //nolint:govet //...
+	//revive:disable:line-length-limit
+	BaseCommand
+	Sender           currencycmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Contract         currencycmds.AddressFlag    `arg:"" name:"contract" help:"timestamp contract account address" required:"true"`
+	ProjectID        string                      `arg:"" name:"project-id" help:"project id" required:"true"`
+	Currency         currencycmds.CurrencyIDFlag `arg:"" name:"currency-id" help:"currency id" required:"true"`
+	Privatekey       currencycmds.PrivatekeyFlag `arg:"" name:"privatekey" help:"privatekey to sign operation" required:"true"`
+	NetworkID        currencycmds.NetworkIDFlag  `arg:"" name:"network-id" help:"network-id" required:"true"`
+	Files            []string                    `arg:"" name:"files" help:"files to timestamp" type:"existingfile"`
+	Hasher           string                      `name:"hasher" help:"file hash function" default:"sha256"`
+	Token            string                      `name:"token" help:"token for operation"`
+	RequestTimestamp uint64                      `name:"request-timestamp" help:"request timestamp; default is now"`
+	Send             bool                        `name:"send" help:"send operations to remote node"`
+	Remote           launch.ConnInfoFlag         `name:"remote" help:"remote node conn info" placeholder:"ConnInfo" default:"localhost:4321"`
+	Timeout          time.Duration               `name:"timeout" help:"timeout" placeholder:"duration" default:"9s"`
+	ClientID         string                      `name:"client-id" help:"client id"`
+	sender           base.Address
+	contract         base.Address
+	//revive:enable:line-length-limit
+}
+
+func (cmd *TimestampStampCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	hashes, err := cmd.hashFiles()
+	if err != nil {
+		return err
+	}
+
+	ops := make([]base.Operation, len(hashes))
+	for i := range hashes {
+		op, err := cmd.createOperation(hashes[i], len(hashes) > 1)
+		if err != nil {
+			return err
+		}
+
+		ops[i] = op
+	}
+
+	if cmd.Send {
+		client, err := newBaseNetworkClient(cmd.Encoders, cmd.Encoder, cmd.NetworkID.NetworkID(), cmd.ClientID)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = client.Close()
+		}()
+
+		for i := range ops {
+			if err := sendOperation(pctx, client, cmd.Remote, cmd.Timeout, ops[i], cmd.Log); err != nil {
+				return err
+			}
+		}
+	}
+
+	var v interface{} = ops
+	if len(ops) == 1 {
+		v = ops[0]
+	}
+
+	b, err := util.MarshalJSONIndent(v)
+	if err != nil {
+		return err
+	}
+
+	cmd.print("%s", b)
+
+	return nil
+}
+
+func (cmd *TimestampStampCommand) parseFlags() error {
+	if len(cmd.Files) < 1 {
+		return errors.Errorf("empty files")
+	}
+
+	if _, found := fileHashers[strings.ToLower(cmd.Hasher)]; !found {
+		return errors.Errorf("unknown hasher, %q; available: %v", cmd.Hasher, fileHasherNames())
+	}
+
+	sender, err := cmd.Sender.Encode(cmd.Encoder)
+	if err != nil {
+		return errors.Wrapf(err, "invalid sender format, %q", cmd.Sender.String())
+	}
+	cmd.sender = sender
+
+	contract, err := cmd.Contract.Encode(cmd.Encoder)
+	if err != nil {
+		return errors.Wrapf(err, "invalid contract account format, %q", cmd.Contract.String())
+	}
+	cmd.contract = contract
+
+	if cmd.RequestTimestamp < 1 {
+		cmd.RequestTimestamp = uint64(time.Now().Unix())
+	}
+
+	if len(cmd.Token) < 1 {
+		cmd.Token = util.UUID().String()
+	}
+
+	return nil
+}
+
+// hashFiles hashes the files. The files of the same hash are timestamped once;
+// their append operations would have the same fact.
+func (cmd *TimestampStampCommand) hashFiles() ([]string, error) {
+	var hashes []string
+	found := map[string]string{}
+
+	for i := range cmd.Files {
+		data, err := HashFile(cmd.Files[i], cmd.Hasher)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "hash %q", cmd.Files[i])
+		}
+
+		if path, ok := found[data]; ok {
+			cmd.Log.Debug().Str("file", cmd.Files[i]).Str("same_with", path).Str("hash", data).
+				Msg("same hash; skipped")
+
+			continue
+		}
+
+		found[data] = cmd.Files[i]
+		hashes = append(hashes, data)
+	}
+
+	return hashes, nil
+}
+
+func (cmd *TimestampStampCommand) createOperation(data string, batch bool) (base.Operation, error) {
+	e := util.StringError("create append operation for %q", data)
+
+	// NOTE each operation of a batch needs its own token to have a unique fact
+	// hash; the hashes of a batch are unique.
+	token := cmd.Token
+	if batch {
+		token = fmt.Sprintf("%s-%s", cmd.Token, data)
+	}
+
+	fact := timestamp.NewAppendFact(
+		[]byte(token),
+		cmd.sender,
+		cmd.contract,
+		cmd.ProjectID,
+		cmd.RequestTimestamp,
+		data,
+		cmd.Currency.CID,
+	)
+
+	op, err := timestamp.NewAppend(fact)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := op.HashSign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return op, nil
+}
+
+type TimestampVerifyCommand struct { //nolint:govet //...
+	BaseCommand
+	Contract string        `arg:"" name:"contract" help:"timestamp contract account address" required:"true"`
+	File     string        `arg:"" name:"file" help:"file to verify" type:"existingfile"`
+	API      string        `name:"api" help:"digest api url" default:"http://localhost:54320"`
+	Hasher   string        `name:"hasher" help:"file hash function" default:"sha256"`
+	Timeout  time.Duration `name:"timeout" help:"timeout" placeholder:"duration" default:"9s"`
+}
+
+type timestampLookupMatch struct {
+	Project      string    `json:"project"`
+	TimestampIdx uint64    `json:"timestampidx"`
+	Height       int64     `json:"height"`
+	ConfirmedAt  time.Time `json:"confirmed_at"`
+}
+
+func (cmd *TimestampVerifyCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	data, err := HashFile(cmd.File, cmd.Hasher)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().Str("file", cmd.File).Str("hash", data).Msg("file hashed")
+
+	u, err := url.Parse(strings.TrimRight(cmd.API, "/") + "/timestamp/" + url.PathEscape(cmd.Contract) + "/lookup")
+	if err != nil {
+		return errors.Wrap(err, "invalid api url")
+	}
+	u.RawQuery = url.Values{"hash": []string{data}}.Encode()
+
+	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return errors.Errorf("no timestamp found for %q, hash %s", cmd.File, data)
+	case res.StatusCode != http.StatusOK:
+		b, _ := io.ReadAll(res.Body)
+
+		return errors.Errorf("lookup failed, %s: %s", res.Status, strings.TrimSpace(string(b)))
+	}
+
+	var body struct {
+		Embedded struct {
+			Matches []struct {
+				Embedded timestampLookupMatch `json:"_embedded"`
+			} `json:"matches"`
+		} `json:"_embedded"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return errors.Wrap(err, "decode lookup response")
+	}
+
+	matches := make([]timestampLookupMatch, len(body.Embedded.Matches))
+	for i := range body.Embedded.Matches {
+		matches[i] = body.Embedded.Matches[i].Embedded
+	}
+
+	b, err := util.MarshalJSONIndent(struct {
+		File    string                 `json:"file"`
+		Hash    string                 `json:"hash"`
+		Matches []timestampLookupMatch `json:"matches"`
+	}{File: cmd.File, Hash: data, Matches: matches})
+	if err != nil {
+		return err
+	}
+
+	cmd.print("%s", b)
+
+	return nil
+}
@@ -0,0 +1,440 @@
+package digest
+
+import (
+	"context"
+
+	didstate "github.com/ProtoconNet/mitum-credential/state"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	daostate "github.com/ProtoconNet/mitum-dao/state"
+	pointstate "github.com/ProtoconNet/mitum-point/state"
+	timestampservice "github.com/ProtoconNet/mitum-timestamp/state"
+	tokenstate "github.com/ProtoconNet/mitum-token/state"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// backfills fill the documents which were stored by the older digest and miss
+// the fields the newer queries rely on. Each backfill must be idempotent; it
+// runs at every start.
+var backfills = []func(context.Context, *currencydigest.Database) error{
+	backfillCredentialLifecycle,
+	backfillCredentialStatus,
+	backfillTimeStampRequestTimestamp,
+	backfillTimeStampDataHash,
+	backfillTokenBalance,
+	backfillPointActivity,
+	backfillTokenAllowance,
+	backfillPointAllowance,
+	backfillDAODelegatorAccounts,
+}
+
+var backfillBatchSize = 1000
+
+// Backfill runs the backfills. It should be called before the digester
+// starts.
+func Backfill(ctx context.Context, st *currencydigest.Database) error {
+	if st.Readonly() {
+		return nil
+	}
+
+	for i := range backfills {
+		if err := backfills[i](ctx, st); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillCredentialStatus assigns the status list indexes to the credentials
+// stored before the status lists were introduced and rebuilds the status list
+// of their templates.
+func backfillCredentialStatus(ctx context.Context, st *currencydigest.Database) error {
+	keys, err := CredentialTemplateKeys(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	for i := range keys {
+		k := credentialTemplateKey{contract: keys[i][0], template: keys[i][1]}
+
+		actives, height, err := CredentialActives(st, k.contract, k.template)
+		if err != nil {
+			return err
+		}
+
+		models, list, err := buildCredentialStatusModels(st, k, actives, height)
+		if err != nil {
+			return err
+		}
+
+		if len(models) < 1 {
+			switch _, err := CredentialStatusListByTemplate(st, k.contract, k.template); {
+			case err == nil:
+				continue
+			case !errors.Is(err, mitumutil.ErrNotFound):
+				return err
+			}
+		}
+
+		if len(models) > 0 {
+			if _, err := st.DatabaseClient().Collection(defaultColNameDIDCredentialStatus).BulkWrite(
+				ctx, models, options.BulkWrite().SetOrdered(true),
+			); err != nil {
+				return err
+			}
+		}
+
+		if _, err := st.DatabaseClient().Collection(defaultColNameDIDStatusList).DeleteMany(
+			ctx,
+			bson.D{{"contract", k.contract}, {"template", k.template}, {"height", bson.D{{"$lte", height}}}},
+		); err != nil {
+			return err
+		}
+
+		if _, err := st.DatabaseClient().Collection(defaultColNameDIDStatusList).BulkWrite(
+			ctx, []mongo.WriteModel{list}, options.BulkWrite().SetOrdered(true),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillStateFields sets the fields built from the state of the documents
+// of col which match filter. The filter should not match the documents once
+// they are filled.
+func backfillStateFields(
+	ctx context.Context,
+	st *currencydigest.Database,
+	col string,
+	filter bson.D,
+	fields func(mitumbase.State) (bson.D, error),
+) error {
+	var models []mongo.WriteModel
+
+	flush := func() error {
+		if len(models) < 1 {
+			return nil
+		}
+
+		_, err := st.DatabaseClient().Collection(col).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		models = nil
+
+		return err
+	}
+
+	if err := st.DatabaseClient().Find(
+		ctx,
+		col,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				ID interface{} `bson:"_id"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			set, err := fields(sta)
+			if err != nil {
+				return false, err
+			}
+
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.D{{"_id", doc.ID}}).
+				SetUpdate(bson.D{{"$set", set}}),
+			)
+
+			if len(models) >= backfillBatchSize {
+				if err := flush(); err != nil {
+					return false, err
+				}
+			}
+
+			return true, nil
+		},
+		options.Find(),
+	); err != nil {
+		return errors.WithMessagef(err, "backfill %s", col)
+	}
+
+	return flush()
+}
+
+// backfillCredentialLifecycle sets valid_from and valid_until of the
+// credentials, which the lifecycle filters rely on.
+func backfillCredentialLifecycle(ctx context.Context, st *currencydigest.Database) error {
+	return backfillStateFields(ctx, st, defaultColNameDIDCredential,
+		bson.D{{"valid_until", bson.D{{"$exists", false}}}},
+		func(sta mitumbase.State) (bson.D, error) {
+			credential, _, err := didstate.StateCredentialValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return bson.D{
+				{"valid_from", int64(credential.ValidFrom())},
+				{"valid_until", int64(credential.ValidUntil())},
+			}, nil
+		},
+	)
+}
+
+// backfillTimeStampRequestTimestamp sets request_timestamp of the timestamp
+// items, which the from and to filters of the items rely on.
+func backfillTimeStampRequestTimestamp(ctx context.Context, st *currencydigest.Database) error {
+	return backfillStateFields(ctx, st, defaultColNameTimeStamp,
+		bson.D{{"isItem", true}, {"request_timestamp", bson.D{{"$exists", false}}}},
+		func(sta mitumbase.State) (bson.D, error) {
+			item, err := timestampservice.StateTimeStampItemValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return bson.D{{"request_timestamp", int64(item.RequestTimeStamp())}}, nil
+		},
+	)
+}
+
+// backfillTimeStampDataHash sets data_hash of the timestamp items, which the
+// lookup by data hash relies on.
+func backfillTimeStampDataHash(ctx context.Context, st *currencydigest.Database) error {
+	return backfillStateFields(ctx, st, defaultColNameTimeStamp,
+		bson.D{{"isItem", true}, {"data_hash", bson.D{{"$exists", false}}}},
+		func(sta mitumbase.State) (bson.D, error) {
+			item, err := timestampservice.StateTimeStampItemValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return bson.D{{"data_hash", item.Data()}}, nil
+		},
+	)
+}
+
+// backfillTokenBalance sets balance and balance_len of the token balances,
+// which the token holders are sorted by.
+func backfillTokenBalance(ctx context.Context, st *currencydigest.Database) error {
+	return backfillStateFields(ctx, st, defaultColNameTokenBalance,
+		bson.D{{"balance_len", bson.D{{"$exists", false}}}},
+		func(sta mitumbase.State) (bson.D, error) {
+			amount, err := tokenstate.StateTokenBalanceValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			balance, balanceLen := amountSortKey(amount)
+
+			return bson.D{{"balance", balance}, {"balance_len", balanceLen}}, nil
+		},
+	)
+}
+
+// backfillDAODelegatorAccounts sets delegator_accounts of the delegators,
+// which the delegations of an account are looked up by.
+func backfillDAODelegatorAccounts(ctx context.Context, st *currencydigest.Database) error {
+	return backfillStateFields(ctx, st, defaultColNameDAODelegators,
+		bson.D{{"delegator_accounts", bson.D{{"$exists", false}}}},
+		func(sta mitumbase.State) (bson.D, error) {
+			delegators, err := daostate.StateDelegatorsValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return bson.D{{"delegator_accounts", daoDelegatorAccounts(delegators)}}, nil
+		},
+	)
+}
+
+// backfillPointActivity builds the point activities of the blocks which were
+// digested before the activities were recorded, from the stored operations.
+// The activities stored with the older amount format are dropped and built
+// again. The operations are found by the point balance states below the
+// oldest activity, and the activities are upserted by the fact hash, so it
+// can be stopped and run again.
+func backfillPointActivity(ctx context.Context, st *currencydigest.Database) error {
+	col := st.DatabaseClient().Collection(defaultColNamePointActivity)
+
+	if _, err := col.DeleteMany(ctx, bson.D{{"amount_len", bson.D{{"$exists", false}}}}); err != nil {
+		return errors.WithMessage(err, "backfill point activity")
+	}
+
+	filter := bson.D{}
+
+	var oldest struct {
+		Height mitumbase.Height `bson:"height"`
+	}
+
+	switch err := col.FindOne(
+		ctx, bson.D{}, options.FindOne().SetSort(bson.D{{"height", 1}}).SetProjection(bson.D{{"height", 1}}),
+	).Decode(&oldest); {
+	case err == nil:
+		filter = bson.D{{"height", bson.D{{"$lt", oldest.Height}}}}
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return errors.WithMessage(err, "backfill point activity")
+	}
+
+	var facts []string
+	found := map[string]struct{}{}
+
+	if err := st.DatabaseClient().Find(
+		ctx,
+		defaultColNamePointBalance,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			ops := sta.Operations()
+			for i := range ops {
+				if _, ok := found[ops[i].String()]; !ok {
+					found[ops[i].String()] = struct{}{}
+					facts = append(facts, ops[i].String())
+				}
+			}
+
+			return true, nil
+		},
+		options.Find(),
+	); err != nil {
+		return errors.WithMessage(err, "backfill point activity")
+	}
+
+	for i := 0; i < len(facts); i += backfillBatchSize {
+		batch := facts[i:min(i+backfillBatchSize, len(facts))]
+
+		var models []mongo.WriteModel
+
+		if err := st.Operations(
+			bson.M{"fact": bson.M{"$in": batch}},
+			true,
+			false,
+			int64(len(batch)),
+			func(_ mitumutil.Hash, va currencydigest.OperationValue) (bool, error) {
+				if !va.InState() {
+					return true, nil
+				}
+
+				docs := NewPointActivityDocs(va.Operation(), va.Height(), va.Index())
+				for j := range docs {
+					models = append(models, mongo.NewReplaceOneModel().
+						SetFilter(bson.D{
+							{"fact_hash", docs[j].factHash},
+							{"address", docs[j].address},
+							{"kind", docs[j].kind},
+						}).
+						SetReplacement(docs[j]).
+						SetUpsert(true),
+					)
+				}
+
+				return true, nil
+			},
+		); err != nil {
+			return errors.WithMessage(err, "backfill point activity")
+		}
+
+		if len(models) < 1 {
+			continue
+		}
+
+		if _, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return errors.WithMessage(err, "backfill point activity")
+		}
+	}
+
+	return nil
+}
+
+// backfillTokenAllowance builds the token allowances from the approve list of
+// the latest token designs, for the designs stored before the allowances were
+// recorded.
+func backfillTokenAllowance(ctx context.Context, st *currencydigest.Database) error {
+	return backfillAllowances(ctx, st, defaultColNameToken, defaultColNameTokenAllowance,
+		func(contract string, sta mitumbase.State) ([]AllowanceDoc, error) {
+			de, err := tokenstate.StateDesignValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return newTokenAllowanceDocs(contract, *de, sta.Height()), nil
+		},
+	)
+}
+
+// backfillPointAllowance builds the point allowances like
+// backfillTokenAllowance.
+func backfillPointAllowance(ctx context.Context, st *currencydigest.Database) error {
+	return backfillAllowances(ctx, st, defaultColNamePoint, defaultColNamePointAllowance,
+		func(contract string, sta mitumbase.State) ([]AllowanceDoc, error) {
+			de, err := pointstate.StateDesignValue(sta)
+			if err != nil {
+				return nil, err
+			}
+
+			return newPointAllowanceDocs(contract, *de, sta.Height()), nil
+		},
+	)
+}
+
+// backfillAllowances replaces the allowances of the contracts with the ones
+// built from the latest design in designCol, unless the allowances of the
+// design are already stored. The design without allowances is built again at
+// every start, which only deletes nothing.
+func backfillAllowances(
+	ctx context.Context,
+	st *currencydigest.Database,
+	designCol, col string,
+	docs func(contract string, sta mitumbase.State) ([]AllowanceDoc, error),
+) error {
+	if err := latestStates(st, designCol, bson.D{}, []string{"contract"}, nil,
+		func(doc bson.M, sta mitumbase.State) error {
+			contract, _ := doc["contract"].(string)
+
+			switch n, err := st.DatabaseClient().Count(
+				ctx,
+				col,
+				bson.D{{"contract", contract}, {"height", bson.D{{"$gte", sta.Height()}}}},
+				options.Count().SetLimit(1),
+			); {
+			case err != nil:
+				return err
+			case n > 0:
+				return nil
+			}
+
+			allowances, err := docs(contract, sta)
+			if err != nil {
+				return err
+			}
+
+			models := make([]mongo.WriteModel, len(allowances)+1)
+			models[0] = mongo.NewDeleteManyModel().SetFilter(bson.D{{"contract", contract}})
+
+			for i := range allowances {
+				models[i+1] = mongo.NewInsertOneModel().SetDocument(allowances[i])
+			}
+
+			_, err = st.DatabaseClient().Collection(col).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true))
+
+			return err
+		},
+	); err != nil {
+		return errors.WithMessagef(err, "backfill %s", col)
+	}
+
+	return nil
+}
@@ -46,11 +46,17 @@ type BlockSession struct {
 	didCredentialModels               []mongo.WriteModel
 	didHolderDIDModels                []mongo.WriteModel
 	didTemplateModels                 []mongo.WriteModel
+	didCredentialStatusModels         []mongo.WriteModel
+	didStatusListModels               []mongo.WriteModel
 	timestampModels                   []mongo.WriteModel
 	tokenModels                       []mongo.WriteModel
 	tokenBalanceModels                []mongo.WriteModel
+	tokenSupplyModels                 []mongo.WriteModel
+	tokenAllowanceModels              []mongo.WriteModel
 	pointModels                       []mongo.WriteModel
 	pointBalanceModels                []mongo.WriteModel
+	pointAllowanceModels              []mongo.WriteModel
+	pointActivityModels               []mongo.WriteModel
 	daoDesignModels                   []mongo.WriteModel
 	daoProposalModels                 []mongo.WriteModel
 	daoDelegatorsModels               []mongo.WriteModel
@@ -67,6 +73,9 @@ type BlockSession struct {
 	balanceAddressList                []string
 	nftMap                            map[uint64]struct{}
 	credentialMap                     map[string]struct{}
+	credentialStatusListMap           map[credentialTemplateKey]struct{}
+	tokenAllowanceMap                 map[string]struct{}
+	pointAllowanceMap                 map[string]struct{}
 }
 
 func NewBlockSession(
@@ -87,15 +96,18 @@ func NewBlockSession(
 	}
 
 	return &BlockSession{
-		st:            nst,
-		block:         blk,
-		ops:           ops,
-		opstree:       opstree,
-		sts:           sts,
-		proposal:      proposal,
-		statesValue:   &sync.Map{},
-		nftMap:        map[uint64]struct{}{},
-		credentialMap: map[string]struct{}{},
+		st:                      nst,
+		block:                   blk,
+		ops:                     ops,
+		opstree:                 opstree,
+		sts:                     sts,
+		proposal:                proposal,
+		statesValue:             &sync.Map{},
+		nftMap:                  map[uint64]struct{}{},
+		credentialMap:           map[string]struct{}{},
+		tokenAllowanceMap:       map[string]struct{}{},
+		pointAllowanceMap:       map[string]struct{}{},
+		credentialStatusListMap: map[credentialTemplateKey]struct{}{},
 	}, nil
 }
 
@@ -129,6 +141,9 @@ func (bs *BlockSession) Prepare() error {
 	if err := bs.preparePoint(); err != nil {
 		return err
 	}
+	if err := bs.preparePointActivities(); err != nil {
+		return err
+	}
 	if err := bs.prepareDAO(); err != nil {
 		return err
 	}
@@ -257,6 +272,30 @@ func (bs *BlockSession) Commit(ctx context.Context) error {
 			}
 		}
 
+		if len(bs.didCredentialStatusModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNameDIDCredentialStatus, bs.didCredentialStatusModels); err != nil {
+				return nil, err
+			}
+		}
+
+		for k := range bs.credentialStatusListMap {
+			if err := bs.st.CleanByHeightColName(
+				txnCtx,
+				bs.block.Manifest().Height(),
+				defaultColNameDIDStatusList,
+				bson.D{{"contract", k.contract}},
+				bson.D{{"template", k.template}},
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bs.didStatusListModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNameDIDStatusList, bs.didStatusListModels); err != nil {
+				return nil, err
+			}
+		}
+
 		if len(bs.didHolderDIDModels) > 0 {
 			if err := bs.writeModels(txnCtx, defaultColNameHolder, bs.didHolderDIDModels); err != nil {
 				return nil, err
@@ -287,6 +326,29 @@ func (bs *BlockSession) Commit(ctx context.Context) error {
 			}
 		}
 
+		for contract := range bs.tokenAllowanceMap {
+			if err := bs.st.CleanByHeightColName(
+				txnCtx,
+				bs.block.Manifest().Height(),
+				defaultColNameTokenAllowance,
+				bson.D{{"contract", contract}},
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bs.tokenAllowanceModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNameTokenAllowance, bs.tokenAllowanceModels); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bs.tokenSupplyModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNameTokenSupply, bs.tokenSupplyModels); err != nil {
+				return nil, err
+			}
+		}
+
 		if len(bs.pointModels) > 0 {
 			if err := bs.writeModels(txnCtx, defaultColNamePoint, bs.pointModels); err != nil {
 				return nil, err
@@ -299,6 +361,29 @@ func (bs *BlockSession) Commit(ctx context.Context) error {
 			}
 		}
 
+		for contract := range bs.pointAllowanceMap {
+			if err := bs.st.CleanByHeightColName(
+				txnCtx,
+				bs.block.Manifest().Height(),
+				defaultColNamePointAllowance,
+				bson.D{{"contract", contract}},
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bs.pointAllowanceModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNamePointAllowance, bs.pointAllowanceModels); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bs.pointActivityModels) > 0 {
+			if err := bs.writeModels(txnCtx, defaultColNamePointActivity, bs.pointActivityModels); err != nil {
+				return nil, err
+			}
+		}
+
 		if len(bs.daoDesignModels) > 0 {
 			if err := bs.writeModels(txnCtx, defaultColNameDAO, bs.daoDesignModels); err != nil {
 				return nil, err
@@ -839,13 +924,22 @@ func (bs *BlockSession) close() error {
 	bs.didCredentialModels = nil
 	bs.didHolderDIDModels = nil
 	bs.didTemplateModels = nil
+	bs.didCredentialStatusModels = nil
+	bs.didStatusListModels = nil
 	bs.timestampModels = nil
 	bs.tokenModels = nil
 	bs.tokenBalanceModels = nil
+	bs.tokenSupplyModels = nil
+	bs.tokenAllowanceModels = nil
 	bs.pointModels = nil
 	bs.pointBalanceModels = nil
+	bs.pointAllowanceModels = nil
+	bs.pointActivityModels = nil
 	bs.nftMap = nil
 	bs.credentialMap = nil
+	bs.credentialStatusListMap = nil
+	bs.tokenAllowanceMap = nil
+	bs.pointAllowanceMap = nil
 
 	return bs.st.Close()
 }
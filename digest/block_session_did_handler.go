@@ -1,7 +1,11 @@
 package digest
 
 import (
+	"sort"
+
 	"github.com/ProtoconNet/mitum-credential/state"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -15,6 +19,7 @@ func (bs *BlockSession) prepareDID() error {
 	var didCredentialModels []mongo.WriteModel
 	var didHolderDIDModels []mongo.WriteModel
 	var didTemplateModels []mongo.WriteModel
+	var credentialStates []mitumbase.State
 
 	for i := range bs.sts {
 		st := bs.sts[i]
@@ -32,6 +37,7 @@ func (bs *BlockSession) prepareDID() error {
 			}
 			bs.credentialMap[st.Key()] = struct{}{}
 			didCredentialModels = append(didCredentialModels, j...)
+			credentialStates = append(credentialStates, st)
 		case state.IsStateHolderDIDKey(st.Key()):
 			j, err := bs.handleHolderDIDState(st)
 			if err != nil {
@@ -54,9 +60,134 @@ func (bs *BlockSession) prepareDID() error {
 	bs.didHolderDIDModels = didHolderDIDModels
 	bs.didTemplateModels = didTemplateModels
 
+	statusModels, statusListModels, err := bs.prepareCredentialStatus(credentialStates)
+	if err != nil {
+		return err
+	}
+
+	bs.didCredentialStatusModels = statusModels
+	bs.didStatusListModels = statusListModels
+
 	return nil
 }
 
+// prepareCredentialStatus assigns status list indexes to the credentials
+// which have none yet and regenerates the status list of every template whose
+// credentials were changed in this block.
+func (bs *BlockSession) prepareCredentialStatus(sts []mitumbase.State) ([]mongo.WriteModel, []mongo.WriteModel, error) {
+	if len(sts) < 1 {
+		return nil, nil, nil
+	}
+
+	var keys []credentialTemplateKey
+	changed := map[credentialTemplateKey]map[string]bool{}
+
+	for i := range sts {
+		st := sts[i]
+
+		parsedKey, err := crcystate.ParseStateKey(st.Key(), state.CredentialPrefix, 5)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, isActive, err := state.StateCredentialValue(st)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		k := credentialTemplateKey{contract: parsedKey[1], template: parsedKey[2]}
+		if _, found := changed[k]; !found {
+			keys = append(keys, k)
+			changed[k] = map[string]bool{}
+		}
+
+		changed[k][parsedKey[3]] = isActive
+	}
+
+	height := bs.block.Manifest().Height()
+
+	var statusModels []mongo.WriteModel
+	var statusListModels []mongo.WriteModel
+
+	for _, k := range keys {
+		actives, _, err := CredentialActives(bs.st, k.contract, k.template)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for id := range changed[k] {
+			actives[id] = changed[k][id]
+		}
+
+		models, list, err := buildCredentialStatusModels(bs.st, k, actives, height)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bs.credentialStatusListMap[k] = struct{}{}
+		statusModels = append(statusModels, models...)
+		statusListModels = append(statusListModels, list)
+	}
+
+	return statusModels, statusListModels, nil
+}
+
+type credentialTemplateKey struct {
+	contract string
+	template string
+}
+
+// buildCredentialStatusModels assigns the next indexes to the credentials of
+// actives which have none, in the order of credential id, and builds the
+// status list of the template at height.
+func buildCredentialStatusModels(
+	st *currencydigest.Database, k credentialTemplateKey, actives map[string]bool, height mitumbase.Height,
+) ([]mongo.WriteModel, mongo.WriteModel, error) {
+	indexes, err := CredentialStatusIndexes(st, k.contract, k.template)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next uint64
+	for id := range indexes {
+		if indexes[id] >= next {
+			next = indexes[id] + 1
+		}
+	}
+
+	var missing []string
+	for id := range actives {
+		if _, found := indexes[id]; !found {
+			missing = append(missing, id)
+		}
+	}
+
+	sort.Strings(missing)
+
+	models := make([]mongo.WriteModel, len(missing))
+	for i, id := range missing {
+		indexes[id] = next
+		models[i] = mongo.NewInsertOneModel().SetDocument(
+			NewCredentialStatusDoc(k.contract, k.template, id, next, height),
+		)
+		next++
+	}
+
+	var bits []uint64
+	for id := range actives {
+		if !actives[id] {
+			bits = append(bits, indexes[id])
+		}
+	}
+
+	doc, err := NewCredentialStatusListDoc(k.contract, k.template, CredentialStatusListSize(next), bits, height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return models, mongo.NewInsertOneModel().SetDocument(doc), nil
+}
+
 func (bs *BlockSession) handleDIDServiceState(st mitumbase.State) ([]mongo.WriteModel, error) {
 	if issuerDoc, err := NewServiceDoc(st, bs.st.DatabaseEncoder()); err != nil {
 		return nil, err
@@ -1,7 +1,9 @@
 package digest
 
 import (
+	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
 	"github.com/ProtoconNet/mitum-point/state"
+	"github.com/ProtoconNet/mitum-point/types"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -13,6 +15,7 @@ func (bs *BlockSession) preparePoint() error {
 
 	var PointModels []mongo.WriteModel
 	var PointBalanceModels []mongo.WriteModel
+	var PointAllowanceModels []mongo.WriteModel
 
 	for i := range bs.sts {
 		st := bs.sts[i]
@@ -24,6 +27,19 @@ func (bs *BlockSession) preparePoint() error {
 				return err
 			}
 			PointModels = append(PointModels, j...)
+
+			stateKeys, err := crcystate.ParseStateKey(st.Key(), state.PointPrefix, 3)
+			if err != nil {
+				return err
+			}
+
+			de, err := state.StateDesignValue(st)
+			if err != nil {
+				return err
+			}
+
+			bs.pointAllowanceMap[stateKeys[1]] = struct{}{}
+			PointAllowanceModels = append(PointAllowanceModels, bs.handlePointAllowances(stateKeys[1], *de)...)
 		case state.IsStatePointBalanceKey(st.Key()):
 			j, err := bs.handlePointBalanceState(st)
 			if err != nil {
@@ -37,6 +53,7 @@ func (bs *BlockSession) preparePoint() error {
 
 	bs.pointModels = PointModels
 	bs.pointBalanceModels = PointBalanceModels
+	bs.pointAllowanceModels = PointAllowanceModels
 
 	return nil
 }
@@ -60,3 +77,63 @@ func (bs *BlockSession) handlePointBalanceState(st mitumbase.State) ([]mongo.Wri
 		}, nil
 	}
 }
+
+func (bs *BlockSession) handlePointAllowances(contract string, de types.Design) []mongo.WriteModel {
+	docs := newPointAllowanceDocs(contract, de, bs.block.Manifest().Height())
+
+	models := make([]mongo.WriteModel, len(docs))
+	for i := range docs {
+		models[i] = mongo.NewInsertOneModel().SetDocument(docs[i])
+	}
+
+	return models
+}
+
+// newPointAllowanceDocs returns the allowances in the approve list of the
+// design.
+func newPointAllowanceDocs(contract string, de types.Design, height mitumbase.Height) []AllowanceDoc {
+	var docs []AllowanceDoc
+
+	approves := de.Policy().ApproveList()
+	for i := range approves {
+		owner := approves[i].Account().String()
+
+		approved := approves[i].Approved()
+		for j := range approved {
+			docs = append(docs, NewAllowanceDoc(
+				contract, owner, approved[j].Account().String(), approved[j].Amount(), height,
+			))
+		}
+	}
+
+	return docs
+}
+
+// preparePointActivities records the balance changes made by the point
+// operations processed in this block.
+func (bs *BlockSession) preparePointActivities() error {
+	if len(bs.ops) < 1 {
+		return nil
+	}
+
+	height := bs.block.Manifest().Height()
+
+	var models []mongo.WriteModel
+
+	for i := range bs.ops {
+		op := bs.ops[i]
+
+		if no, found := bs.opsTreeNodes[op.Fact().Hash().String()]; !found || !no.InState() {
+			continue
+		}
+
+		docs := NewPointActivityDocs(op, height, uint64(i))
+		for j := range docs {
+			models = append(models, mongo.NewInsertOneModel().SetDocument(docs[j]))
+		}
+	}
+
+	bs.pointActivityModels = models
+
+	return nil
+}
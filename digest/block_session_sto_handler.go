@@ -16,7 +16,7 @@ func (bs *BlockSession) prepareSTO() error {
 	var stoHolderPartitionBalanceModels []mongo.WriteModel
 	var stoHolderPartitionOperatorsModels []mongo.WriteModel
 	var stoPartitionBalanceModels []mongo.WriteModel
-	//var stoPartitionControllersModels []mongo.WriteModel
+	var stoPartitionControllersModels []mongo.WriteModel
 	var stoOperatorHoldersModels []mongo.WriteModel
 
 	for i := range bs.sts {
@@ -52,12 +52,12 @@ func (bs *BlockSession) prepareSTO() error {
 				return err
 			}
 			stoPartitionBalanceModels = append(stoPartitionBalanceModels, j...)
-		//case stostate.IsStatePartitionControllersKey(st.Key()):
-		//	j, err := bs.handlePartitionControllersState(st)
-		//	if err != nil {
-		//		return err
-		//	}
-		//	stoPartitionControllersModels = append(stoPartitionControllersModels, j...)
+		case ststo.IsStatePartitionControllersKey(st.Key()):
+			j, err := bs.handleSTOPartitionControllersState(st)
+			if err != nil {
+				return err
+			}
+			stoPartitionControllersModels = append(stoPartitionControllersModels, j...)
 		case ststo.IsStateOperatorTokenHoldersKey(st.Key()):
 			j, err := bs.handleSTOperatorHoldersState(st)
 			if err != nil {
@@ -74,7 +74,7 @@ func (bs *BlockSession) prepareSTO() error {
 	bs.stoHolderPartitionBalanceModels = stoHolderPartitionBalanceModels
 	bs.stoHolderPartitionOperatorsModels = stoHolderPartitionOperatorsModels
 	bs.stoPartitionBalanceModels = stoPartitionBalanceModels
-	//bs.stoPartitionControllersModels = stoPartitionControllersModels
+	bs.stoPartitionControllersModels = stoPartitionControllersModels
 	bs.stoOperatorHoldersModels = stoOperatorHoldersModels
 
 	return nil
@@ -130,6 +130,16 @@ func (bs *BlockSession) handleSTOPartitionBalanceState(st base.State) ([]mongo.W
 	}
 }
 
+func (bs *BlockSession) handleSTOPartitionControllersState(st base.State) ([]mongo.WriteModel, error) {
+	if doc, err := NewSTOPartitionControllersDoc(st, bs.st.DatabaseEncoder()); err != nil {
+		return nil, err
+	} else {
+		return []mongo.WriteModel{
+			mongo.NewInsertOneModel().SetDocument(doc),
+		}, nil
+	}
+}
+
 func (bs *BlockSession) handleSTOperatorHoldersState(st base.State) ([]mongo.WriteModel, error) {
 	if doc, err := NewSTOOperatorHoldersDoc(st, bs.st.DatabaseEncoder()); err != nil {
 		return nil, err
@@ -1,7 +1,10 @@
 package digest
 
 import (
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
 	"github.com/ProtoconNet/mitum-token/state"
+	"github.com/ProtoconNet/mitum-token/types"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -13,6 +16,18 @@ func (bs *BlockSession) prepareToken() error {
 
 	var TokenModels []mongo.WriteModel
 	var TokenBalanceModels []mongo.WriteModel
+	var TokenAllowanceModels []mongo.WriteModel
+
+	var contracts []string
+	designs := map[string]types.Design{}
+	balances := map[string]map[string]common.Big{}
+
+	addContract := func(contract string) {
+		if _, found := balances[contract]; !found {
+			contracts = append(contracts, contract)
+			balances[contract] = map[string]common.Big{}
+		}
+	}
 
 	for i := range bs.sts {
 		st := bs.sts[i]
@@ -24,12 +39,41 @@ func (bs *BlockSession) prepareToken() error {
 				return err
 			}
 			TokenModels = append(TokenModels, j...)
+
+			stateKeys, err := crcystate.ParseStateKey(st.Key(), state.TokenPrefix, 3)
+			if err != nil {
+				return err
+			}
+
+			de, err := state.StateDesignValue(st)
+			if err != nil {
+				return err
+			}
+
+			addContract(stateKeys[1])
+			designs[stateKeys[1]] = *de
+
+			bs.tokenAllowanceMap[stateKeys[1]] = struct{}{}
+			TokenAllowanceModels = append(TokenAllowanceModels, bs.handleTokenAllowances(stateKeys[1], *de)...)
 		case state.IsStateTokenBalanceKey(st.Key()):
 			j, err := bs.handleTokenBalanceState(st)
 			if err != nil {
 				return err
 			}
 			TokenBalanceModels = append(TokenBalanceModels, j...)
+
+			stateKeys, err := crcystate.ParseStateKey(st.Key(), state.TokenPrefix, 4)
+			if err != nil {
+				return err
+			}
+
+			amount, err := state.StateTokenBalanceValue(st)
+			if err != nil {
+				return err
+			}
+
+			addContract(stateKeys[1])
+			balances[stateKeys[1]][stateKeys[2]] = amount
 		default:
 			continue
 		}
@@ -37,10 +81,81 @@ func (bs *BlockSession) prepareToken() error {
 
 	bs.tokenModels = TokenModels
 	bs.tokenBalanceModels = TokenBalanceModels
+	bs.tokenAllowanceModels = TokenAllowanceModels
+
+	supplyModels, err := bs.prepareTokenSupply(contracts, designs, balances)
+	if err != nil {
+		return err
+	}
+	bs.tokenSupplyModels = supplyModels
 
 	return nil
 }
 
+// prepareTokenSupply builds the supply snapshot of each token changed in this
+// block from the previous snapshot, or the stored balances without one, and
+// the balance differences.
+func (bs *BlockSession) prepareTokenSupply(
+	contracts []string,
+	designs map[string]types.Design,
+	balances map[string]map[string]common.Big,
+) ([]mongo.WriteModel, error) {
+	var models []mongo.WriteModel
+
+	for _, contract := range contracts {
+		prev, err := LatestTokenSupply(bs.st, contract)
+		if err != nil {
+			return nil, err
+		}
+
+		total := common.ZeroBig
+		circulating := common.ZeroBig
+		var holders int64
+
+		if prev != nil {
+			total = prev.TotalSupply
+			circulating = prev.CirculatingSupply
+			holders = prev.Holders
+		} else {
+			// NOTE the first snapshot of the token, which may hold balances
+			// indexed before the snapshots, starts from the stored balances.
+			if de, err := Token(bs.st, contract); err == nil {
+				total = de.Policy().TotalSupply()
+			}
+
+			if circulating, holders, err = TokenBalanceSummary(bs.st, contract); err != nil {
+				return nil, err
+			}
+		}
+
+		if de, found := designs[contract]; found {
+			total = de.Policy().TotalSupply()
+		}
+
+		for account, amount := range balances[contract] {
+			before, _, err := latestTokenBalance(bs.st, contract, account)
+			if err != nil {
+				return nil, err
+			}
+
+			circulating = circulating.Add(amount).Sub(before)
+
+			switch {
+			case before.OverZero() && !amount.OverZero():
+				holders--
+			case !before.OverZero() && amount.OverZero():
+				holders++
+			}
+		}
+
+		models = append(models, mongo.NewInsertOneModel().SetDocument(
+			NewTokenSupplyDoc(contract, total, circulating, holders, bs.block.Manifest().Height()),
+		))
+	}
+
+	return models, nil
+}
+
 func (bs *BlockSession) handleTokenState(st mitumbase.State) ([]mongo.WriteModel, error) {
 	if tokenDoc, err := NewTokenDoc(st, bs.st.DatabaseEncoder()); err != nil {
 		return nil, err
@@ -60,3 +175,34 @@ func (bs *BlockSession) handleTokenBalanceState(st mitumbase.State) ([]mongo.Wri
 		}, nil
 	}
 }
+
+func (bs *BlockSession) handleTokenAllowances(contract string, de types.Design) []mongo.WriteModel {
+	docs := newTokenAllowanceDocs(contract, de, bs.block.Manifest().Height())
+
+	models := make([]mongo.WriteModel, len(docs))
+	for i := range docs {
+		models[i] = mongo.NewInsertOneModel().SetDocument(docs[i])
+	}
+
+	return models
+}
+
+// newTokenAllowanceDocs returns the allowances in the approve list of the
+// design.
+func newTokenAllowanceDocs(contract string, de types.Design, height mitumbase.Height) []AllowanceDoc {
+	var docs []AllowanceDoc
+
+	approves := de.Policy().ApproveList()
+	for i := range approves {
+		owner := approves[i].Account().String()
+
+		approved := approves[i].Approved()
+		for j := range approved {
+			docs = append(docs, NewAllowanceDoc(
+				contract, owner, approved[j].Account().String(), approved[j].Amount(), height,
+			))
+		}
+	}
+
+	return docs
+}
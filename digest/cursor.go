@@ -0,0 +1,357 @@
+package digest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// PageCursor is the position in the list. It is handed to the clients as an
+// opaque token signed by the node, so the clients can not make up the
+// position. Height is the last block when the first page was loaded; the
+// following pages do not show the states after it.
+type PageCursor struct {
+	Key     string           `json:"k"`
+	Height  mitumbase.Height `json:"h"`
+	Reverse bool             `json:"r,omitempty"`
+	Prev    bool             `json:"p,omitempty"`
+}
+
+func newCursorSecret() []byte {
+	b := make([]byte, sha256.Size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// SetCursorSecret sets the key to sign the cursors, cursor_secret of Design.
+// The nodes behind the same endpoint should share the secret; by default
+// every node has it's own random secret, so the cursors are invalid after
+// restart.
+func (hd *Handlers) SetCursorSecret(b []byte) *Handlers {
+	if len(b) > 0 {
+		hd.cursorSecret = b
+	}
+
+	return hd
+}
+
+func (hd *Handlers) signCursor(b []byte) []byte {
+	mac := hmac.New(sha256.New, hd.cursorSecret)
+	_, _ = mac.Write(b)
+
+	return mac.Sum(nil)
+}
+
+func (hd *Handlers) EncodeCursor(c PageCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b) + "." +
+		base64.RawURLEncoding.EncodeToString(hd.signCursor(b)), nil
+}
+
+func (hd *Handlers) DecodeCursor(s string) (PageCursor, error) {
+	var c PageCursor
+
+	e := errors.Errorf("invalid cursor, %q", s)
+
+	body, sig, found := strings.Cut(s, ".")
+	if !found {
+		return c, e
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return c, e
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, hd.signCursor(b)) {
+		return c, e
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, e
+	}
+
+	return c, nil
+}
+
+// pageQuery is the paging of list request. The position is given by cursor,
+// or by offset for the old clients.
+type pageQuery struct {
+	cursor  string
+	offset  string
+	reverse bool
+	prev    bool
+	limit   int64
+	height  mitumbase.Height
+	total   bool
+}
+
+func (hd *Handlers) parsePageQuery(r *http.Request) (pageQuery, error) {
+	q := pageQuery{
+		cursor: currencydigest.ParseStringQuery(r.URL.Query().Get("cursor")),
+		limit:  currencydigest.ParseLimitQuery(r.URL.Query().Get("limit")),
+		total:  currencydigest.ParseBoolQuery(r.URL.Query().Get("total")),
+	}
+
+	if len(q.cursor) < 1 {
+		q.offset = currencydigest.ParseStringQuery(r.URL.Query().Get("offset"))
+		q.reverse = currencydigest.ParseBoolQuery(r.URL.Query().Get("reverse"))
+		q.height = hd.database.LastBlock()
+
+		return q, nil
+	}
+
+	c, err := hd.DecodeCursor(q.cursor)
+	if err != nil {
+		return q, err
+	}
+
+	q.offset = c.Key
+	q.reverse = c.Reverse
+	q.prev = c.Prev
+	q.height = c.Height
+
+	return q, nil
+}
+
+// pageLimit is the number of items of the page. Without valid limit query, it is
+// from the items limiter of the request type. It is bounded by maxLimit like
+// the database queries, so the filled page is always followed by the next
+// link.
+func (hd *Handlers) pageLimit(q pageQuery, name string) int64 {
+	limit := q.limit
+	if limit < 1 {
+		limit = hd.itemsLimiter(name)
+	}
+
+	return min(limit, maxLimit)
+}
+
+// scanReverse is the order to load the items; the items before the offset are
+// loaded backward and should be reversed by reverseItems.
+func (q pageQuery) scanReverse() bool {
+	return q.reverse != q.prev
+}
+
+func (q pageQuery) cacheKeys() []string {
+	return []string{
+		currencydigest.StringOffsetQuery(q.offset),
+		currencydigest.StringBoolQuery("reverse", q.reverse),
+		currencydigest.StringBoolQuery("prev", q.prev),
+		currencydigest.StringBoolQuery("total", q.total),
+		"height=" + q.height.String(),
+		strconv.FormatInt(q.limit, 10),
+	}
+}
+
+// expire is for the cache of the page; the filled page which is not the first
+// one hardly changes.
+func (q pageQuery) expire(notFilled time.Duration, filled bool) time.Duration {
+	if len(q.offset) > 0 && filled {
+		return time.Minute
+	}
+
+	return notFilled
+}
+
+func reverseItems[T any](items []T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// pageItems returns the page of the whole list for the lists which are
+// built in memory. The items are in the listed order and have unique keys;
+// they should be built at the height of q, so the offset stays in the list.
+// It returns the page, the keys of the first and the last items and whether
+// the page is filled.
+func pageItems[T any](items []T, key func(T) string, q pageQuery, limit int64) ([]T, string, string, bool, error) {
+	if q.reverse {
+		items = append([]T(nil), items...)
+		reverseItems(items)
+	}
+
+	start, end := 0, len(items)
+
+	if len(q.offset) > 0 {
+		i := -1
+		for j := range items {
+			if key(items[j]) == q.offset {
+				i = j
+
+				break
+			}
+		}
+
+		switch {
+		case i < 0:
+			return nil, "", "", false, mitumutil.ErrNotFound.Errorf("item of offset, %q", q.offset)
+		case q.prev:
+			end = i
+		default:
+			start = i + 1
+		}
+	}
+
+	switch {
+	case limit < 1:
+	case q.prev:
+		start = max(end-int(limit), 0)
+	default:
+		end = min(start+int(limit), end)
+	}
+
+	page := items[start:end]
+
+	var first, last string
+	if len(page) > 0 {
+		first = key(page[0])
+		last = key(page[len(page)-1])
+	}
+
+	return page, first, last, limit > 0 && int64(len(page)) == limit, nil
+}
+
+// selfPageURL is the url of the requested page.
+func (q pageQuery) selfPageURL(baseSelf string) string {
+	switch {
+	case len(q.cursor) > 0:
+		return q.withLimit(currencydigest.AddQueryValue(baseSelf, "cursor="+q.cursor))
+	case len(q.offset) > 0:
+		baseSelf = currencydigest.AddQueryValue(baseSelf, currencydigest.StringOffsetQuery(q.offset))
+	}
+
+	if q.reverse {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", q.reverse))
+	}
+
+	return q.withLimit(baseSelf)
+}
+
+// withLimit keeps the limit query of the request in the links, so the
+// following pages have the same size.
+func (q pageQuery) withLimit(u string) string {
+	if q.limit < 1 {
+		return u
+	}
+
+	return currencydigest.AddQueryValue(u, "limit="+strconv.FormatInt(q.limit, 10))
+}
+
+// pageLink is the link of the list page with its relation.
+type pageLink struct {
+	rel  string
+	href string
+}
+
+// pageLinks builds the next, prev and reverse links of the list page. first
+// and last are the keys of the first and the last items in the listed order.
+func (hd *Handlers) pageLinks(
+	baseSelf string,
+	q pageQuery,
+	first, last string,
+	filled bool,
+) ([]pageLink, error) {
+	link := func(c PageCursor) (string, error) {
+		s, err := hd.EncodeCursor(c)
+		if err != nil {
+			return "", err
+		}
+
+		u := q.withLimit(currencydigest.AddQueryValue(baseSelf, "cursor="+s))
+		if q.total {
+			u = currencydigest.AddQueryValue(u, currencydigest.StringBoolQuery("total", q.total))
+		}
+
+		return u, nil
+	}
+
+	var links []pageLink
+
+	if len(last) > 0 && (filled || q.prev) {
+		next, err := link(PageCursor{Key: last, Height: q.height, Reverse: q.reverse})
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, pageLink{rel: "next", href: next})
+	}
+
+	if len(first) > 0 && ((q.prev && filled) || (!q.prev && len(q.offset) > 0)) {
+		prev, err := link(PageCursor{Key: first, Height: q.height, Reverse: q.reverse, Prev: true})
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, pageLink{rel: "prev", href: prev})
+	}
+
+	links = append(links, pageLink{
+		rel:  "reverse",
+		href: q.withLimit(currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", !q.reverse))),
+	})
+
+	return links, nil
+}
+
+// linkHeader formats the links for the Link header, for the responses which
+// have no place for the links in the body.
+func linkHeader(links []pageLink) string {
+	s := make([]string, len(links))
+	for i := range links {
+		s[i] = "<" + links[i].href + `>; rel="` + links[i].rel + `"`
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// addPageLinks adds the next, prev and reverse links of the list page. first
+// and last are the keys of the first and the last items in the listed order.
+// total counts the items of the whole list when the client asks.
+func (hd *Handlers) addPageLinks(
+	hal currencydigest.Hal,
+	baseSelf string,
+	q pageQuery,
+	first, last string,
+	filled bool,
+	total func() (int64, error),
+) (currencydigest.Hal, error) {
+	links, err := hd.pageLinks(baseSelf, q, first, last, filled)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range links {
+		hal = hal.AddLink(links[i].rel, currencydigest.NewHalLink(links[i].href, nil))
+	}
+
+	if q.total && total != nil {
+		n, err := total()
+		if err != nil {
+			return nil, err
+		}
+
+		hal = hal.AddExtras("total", n)
+	}
+
+	return hal, nil
+}
@@ -0,0 +1,214 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPageCursor(t *testing.T) {
+	hd := (&Handlers{cursorSecret: newCursorSecret()}).SetCursorSecret([]byte(strings.Repeat("a", 32)))
+
+	c := PageCursor{Key: "k,1", Height: 33, Reverse: true, Prev: true}
+
+	s, err := hd.EncodeCursor(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, sig, _ := strings.Cut(s, ".")
+
+	cases := []struct {
+		name   string
+		cursor string
+		secret string
+		err    bool
+	}{
+		{name: "signed", cursor: s},
+		{name: "same secret on other node", cursor: s, secret: strings.Repeat("a", 32)},
+		{name: "other secret", cursor: s, secret: strings.Repeat("b", 32), err: true},
+		{name: "no signature", cursor: body, err: true},
+		{name: "changed body", cursor: "e30." + sig, err: true},
+		{name: "changed signature", cursor: body + "." + strings.Repeat("A", len(sig)), err: true},
+		{name: "not base64", cursor: "!." + sig, err: true},
+		{name: "empty", cursor: "", err: true},
+	}
+
+	for _, c0 := range cases {
+		t.Run(c0.name, func(t *testing.T) {
+			d := hd
+			if len(c0.secret) > 0 {
+				d = (&Handlers{}).SetCursorSecret([]byte(c0.secret))
+			}
+
+			back, err := d.DecodeCursor(c0.cursor)
+			switch {
+			case c0.err && err == nil:
+				t.Fatal("expected error")
+			case c0.err:
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if back != c {
+				t.Errorf("expected %+v, but %+v", c, back)
+			}
+		})
+	}
+
+	t.Run("empty secret keeps random secret", func(t *testing.T) {
+		d := (&Handlers{cursorSecret: newCursorSecret()}).SetCursorSecret(nil)
+		if len(d.cursorSecret) < 1 {
+			t.Fatal("expected random secret")
+		}
+
+		if _, err := d.DecodeCursor(s); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestPageItems(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	cases := []struct {
+		name   string
+		q      pageQuery
+		limit  int64
+		page   string
+		first  string
+		last   string
+		filled bool
+		err    bool
+	}{
+		{name: "first page", limit: 2, page: "ab", first: "a", last: "b", filled: true},
+		{name: "next page", q: pageQuery{offset: "b"}, limit: 2, page: "cd", first: "c", last: "d", filled: true},
+		{name: "last page", q: pageQuery{offset: "d"}, limit: 2, page: "e", first: "e", last: "e"},
+		{name: "after last", q: pageQuery{offset: "e"}, limit: 2, page: ""},
+		{name: "no limit", limit: -1, page: "abcde", first: "a", last: "e"},
+		{name: "reverse", q: pageQuery{reverse: true}, limit: 2, page: "ed", first: "e", last: "d", filled: true},
+		{name: "reverse next", q: pageQuery{offset: "d", reverse: true}, limit: 2, page: "cb", first: "c", last: "b", filled: true},
+		{name: "prev", q: pageQuery{offset: "e", prev: true}, limit: 2, page: "cd", first: "c", last: "d", filled: true},
+		{name: "prev to first", q: pageQuery{offset: "b", prev: true}, limit: 2, page: "a", first: "a", last: "a"},
+		{name: "reverse prev", q: pageQuery{offset: "b", reverse: true, prev: true}, limit: 2, page: "dc", first: "d", last: "c", filled: true},
+		{name: "unknown offset", q: pageQuery{offset: "z"}, limit: 2, err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			page, first, last, filled, err := pageItems(items, func(s string) string { return s }, c.q, c.limit)
+			switch {
+			case c.err && err == nil:
+				t.Fatal("expected error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if s := strings.Join(page, ""); s != c.page {
+				t.Errorf("expected page %q, but %q", c.page, s)
+			}
+
+			if first != c.first || last != c.last {
+				t.Errorf("expected first and last %q, %q, but %q, %q", c.first, c.last, first, last)
+			}
+
+			if filled != c.filled {
+				t.Errorf("expected filled %v, but %v", c.filled, filled)
+			}
+		})
+	}
+
+	if strings.Join(items, "") != "abcde" {
+		t.Errorf("items changed, %v", items)
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	hd := (&Handlers{cursorSecret: newCursorSecret()}).SetCursorSecret([]byte(strings.Repeat("a", 32)))
+
+	cases := []struct {
+		name   string
+		q      pageQuery
+		filled bool
+		rels   string
+	}{
+		{name: "first filled", filled: true, rels: "next,reverse"},
+		{name: "first not filled", rels: "reverse"},
+		{name: "middle", q: pageQuery{offset: "a"}, filled: true, rels: "next,prev,reverse"},
+		{name: "last", q: pageQuery{offset: "a"}, rels: "prev,reverse"},
+		{name: "prev not filled", q: pageQuery{offset: "a", prev: true}, rels: "next,reverse"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			links, err := hd.pageLinks("/a", c.q, "b", "c", c.filled)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rels := make([]string, len(links))
+			for i := range links {
+				rels[i] = links[i].rel
+
+				if !strings.HasPrefix(links[i].href, "/a?") {
+					t.Errorf("unexpected href, %q", links[i].href)
+				}
+			}
+
+			if s := strings.Join(rels, ","); s != c.rels {
+				t.Errorf("expected rels %q, but %q", c.rels, s)
+			}
+		})
+	}
+
+	t.Run("limit kept", func(t *testing.T) {
+		links, err := hd.pageLinks("/a", pageQuery{offset: "a", limit: 3}, "b", "c", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range links {
+			if !strings.Contains(links[i].href, "limit=3") {
+				t.Errorf("expected limit in %s link, but %q", links[i].rel, links[i].href)
+			}
+		}
+
+		if s := (pageQuery{cursor: "x", limit: 3}).selfPageURL("/a"); !strings.Contains(s, "limit=3") {
+			t.Errorf("expected limit in self link, but %q", s)
+		}
+	})
+
+	if s := linkHeader([]pageLink{{rel: "next", href: "/a?cursor=b"}, {rel: "reverse", href: "/a?reverse=true"}}); s != `</a?cursor=b>; rel="next", </a?reverse=true>; rel="reverse"` {
+		t.Errorf("unexpected Link header, %q", s)
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	hd := &Handlers{itemsLimiter: func(string) int64 { return 10 }}
+
+	cases := []struct {
+		name     string
+		limit    int64
+		expected int64
+	}{
+		{name: "no limit", limit: -1, expected: 10},
+		{name: "zero", limit: 0, expected: 10},
+		{name: "limit", limit: 3, expected: 3},
+		{name: "over max", limit: 1000000, expected: maxLimit},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if limit := hd.pageLimit(pageQuery{limit: c.limit}, "a"); limit != c.expected {
+				t.Errorf("expected %d, but %d", c.expected, limit)
+			}
+		})
+	}
+
+	hd.itemsLimiter = func(string) int64 { return 100 }
+	if limit := hd.pageLimit(pageQuery{limit: -1}, "a"); limit != maxLimit {
+		t.Errorf("expected %d, but %d", maxLimit, limit)
+	}
+}
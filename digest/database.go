@@ -1,5 +1,20 @@
 package digest
 
+import (
+	"context"
+	"fmt"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	currencytypes "github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
 var maxLimit int64 = 50
 
 var (
@@ -16,11 +31,17 @@ var (
 	defaultColNameDIDCredential               = "digest_did_credential"
 	defaultColNameHolder                      = "digest_did_holder_did"
 	defaultColNameTemplate                    = "digest_did_template"
+	defaultColNameDIDCredentialStatus         = "digest_did_credential_status"
+	defaultColNameDIDStatusList               = "digest_did_status_list"
 	defaultColNameTimeStamp                   = "digest_ts"
 	defaultColNameToken                       = "digest_token"
 	defaultColNameTokenBalance                = "digest_token_bl"
+	defaultColNameTokenSupply                 = "digest_token_supply"
+	defaultColNameTokenAllowance              = "digest_token_allowance"
 	defaultColNamePoint                       = "digest_point"
 	defaultColNamePointBalance                = "digest_point_bl"
+	defaultColNamePointAllowance              = "digest_point_allowance"
+	defaultColNamePointActivity               = "digest_point_activity"
 	defaultColNameDAO                         = "digest_dao_de"
 	defaultColNameDAOProposal                 = "digest_dao_pr"
 	defaultColNameDAODelegators               = "digest_dao_dac"
@@ -34,3 +55,226 @@ var (
 	defaultColNameSTOPartitionControllers     = "digest_sto_pt_cac"
 	defaultColNameSTOOperatorHolders          = "digest_sto_oac_hac"
 )
+
+// LastBlockTime returns the confirmed time of the last digested block.
+func LastBlockTime(st *currencydigest.Database) (time.Time, error) {
+	return BlockTime(st, st.LastBlock())
+}
+
+// BlockTime returns the confirmed time of the block at height. The proposed
+// time of the manifest is used when the confirmed time is not available.
+func BlockTime(st *currencydigest.Database, height base.Height) (time.Time, error) {
+	m, _, confirmed, _, _, err := st.ManifestByHeight(height)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, confirmed); err == nil {
+		return t, nil
+	}
+
+	return m.ProposedAt(), nil
+}
+
+// BlockHeightSince returns the first height up to height of which the block
+// time is not before t; the block time grows with the height. When every
+// block is before t, it returns height+1.
+func BlockHeightSince(st *currencydigest.Database, t time.Time, height base.Height) (base.Height, error) {
+	lo, hi := base.GenesisHeight, height+1
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		bt, err := BlockTime(st, mid)
+		if err != nil {
+			return base.NilHeight, err
+		}
+
+		if bt.Before(t) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo, nil
+}
+
+// CurrencyDesign returns the latest design of the currency.
+func CurrencyDesign(st *currencydigest.Database, cid string) (*currencytypes.CurrencyDesign, error) {
+	filter := util.NewBSONFilter("currency", cid)
+
+	var design currencytypes.CurrencyDesign
+	if err := st.DatabaseClient().GetByFilter(
+		defaultColNameCurrency,
+		filter.D(),
+		func(res *mongo.SingleResult) error {
+			sta, err := currencydigest.LoadState(res.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return err
+			}
+
+			design, err = statecurrency.StateCurrencyDesignValue(sta)
+
+			return err
+		},
+		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+	); err != nil {
+		return nil, err
+	}
+
+	return &design, nil
+}
+
+// CurrencyBalances returns the latest balances of the accounts in the
+// currency; the accounts without balance are not in the result. With height,
+// the states after the height are ignored.
+func CurrencyBalances(
+	st *currencydigest.Database,
+	addresses []string,
+	cid string,
+	height *base.Height,
+) (map[string]currencytypes.Amount, error) {
+	amounts := map[string]currencytypes.Amount{}
+	if len(addresses) < 1 {
+		return amounts, nil
+	}
+
+	match := bson.D{{"address", bson.D{{"$in", addresses}}}, {"currency", cid}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	if err := latestStates(st, defaultColNameBalance, match, []string{"address"}, nil,
+		func(doc bson.M, sta base.State) error {
+			amount, err := statecurrency.StateBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if address, ok := doc["address"].(string); ok {
+				amounts[address] = amount
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return amounts, nil
+}
+
+// latestStates calls callback with the latest state of each group of the
+// documents matched in the collection col. The documents are grouped by the
+// fields of keys, and the stages are applied to the groups, whose _id holds
+// the keys, before the latest documents are restored.
+func latestStates(
+	st *currencydigest.Database,
+	col string,
+	match bson.D,
+	keys []string,
+	stages mongo.Pipeline,
+	callback func(doc bson.M, sta base.State) error,
+) error {
+	id := bson.D{}
+	for i := range keys {
+		id = append(id, bson.E{Key: keys[i], Value: "$" + keys[i]})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+		{{"$sort", bson.D{{"height", -1}}}},
+		{{"$group", bson.D{
+			{"_id", id},
+			{"doc", bson.D{{"$first", "$$ROOT"}}},
+		}}},
+	}
+
+	pipeline = append(pipeline, stages...)
+	pipeline = append(pipeline, bson.D{{"$replaceRoot", bson.D{{"newRoot", "$doc"}}}})
+
+	cursor, err := st.DatabaseClient().Collection(col).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	for cursor.Next(context.Background()) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+		if err != nil {
+			return err
+		}
+
+		if err := callback(doc, sta); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// latestStatesOf calls callback with the latest state of each group which has
+// any document matched by match, while the latest document of the group may
+// not match; the callback should check it. The groups are found by match
+// first, so match should be indexed.
+func latestStatesOf(
+	st *currencydigest.Database,
+	col string,
+	match bson.D,
+	keys []string,
+	callback func(doc bson.M, sta base.State) error,
+) error {
+	projection := bson.D{}
+	for i := range keys {
+		projection = append(projection, bson.E{Key: keys[i], Value: 1})
+	}
+
+	var groups bson.A
+	found := map[string]struct{}{}
+
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		col,
+		match,
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			group := bson.D{}
+			var id string
+			for i := range keys {
+				group = append(group, bson.E{Key: keys[i], Value: doc[keys[i]]})
+				id += fmt.Sprintf("%v\x00", doc[keys[i]])
+			}
+
+			if _, ok := found[id]; !ok {
+				found[id] = struct{}{}
+				groups = append(groups, group)
+			}
+
+			return true, nil
+		},
+		options.Find().SetProjection(projection),
+	); err != nil {
+		return err
+	}
+
+	if len(groups) < 1 {
+		return nil
+	}
+
+	return latestStates(st, col, bson.D{{"$or", groups}}, keys, nil, callback)
+}
@@ -0,0 +1,66 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Allowance struct {
+	Owner   string           `json:"owner"`
+	Spender string           `json:"spender"`
+	Amount  common.Big       `json:"amount"`
+	Height  mitumbase.Height `json:"height"`
+}
+
+// AllowancesByOwner returns the allowances the owner granted in the token or
+// point contract; col is the allowance collection of the model.
+func AllowancesByOwner(st *currencydigest.Database, col, contract, owner, spender string) ([]Allowance, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("owner", owner)
+	if len(spender) > 0 {
+		filter = filter.Add("spender", spender)
+	}
+
+	var allowances []Allowance
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		col,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Owner   string           `bson:"owner"`
+				Spender string           `bson:"spender"`
+				Amount  string           `bson:"amount"`
+				Height  mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			amount, err := common.NewBigFromString(doc.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			allowances = append(allowances, Allowance{
+				Owner:   doc.Owner,
+				Spender: doc.Spender,
+				Amount:  amount,
+				Height:  doc.Height,
+			})
+
+			return true, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("spender", 1).D()),
+	); err != nil {
+		return nil, err
+	}
+
+	return allowances, nil
+}
@@ -0,0 +1,319 @@
+package digest
+
+import (
+	"context"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/state/extension"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type contractModel struct {
+	col    string
+	filter bson.D
+}
+
+// contractModels maps the model names to the collections of their designs.
+var contractModels = map[string]contractModel{
+	"nft":        {col: defaultColNameNFTCollection},
+	"credential": {col: defaultColNameDIDCredentialService},
+	"timestamp":  {col: defaultColNameTimeStamp, filter: bson.D{{"isItem", false}}},
+	"token":      {col: defaultColNameToken},
+	"point":      {col: defaultColNamePoint},
+	"dao":        {col: defaultColNameDAO},
+	"sto":        {col: defaultColNameSTO},
+}
+
+var contractModelNames = []string{"nft", "credential", "timestamp", "token", "point", "dao", "sto"}
+
+// IsContractModel reports whether s is a known model name.
+func IsContractModel(s string) bool {
+	_, found := contractModels[s]
+
+	return found
+}
+
+type ContractModelInfo struct {
+	Model  string           `json:"model"`
+	Height mitumbase.Height `json:"height"`
+}
+
+type ContractInfo struct {
+	Address string              `json:"address"`
+	Owner   string              `json:"owner"`
+	Active  bool                `json:"active"`
+	Height  mitumbase.Height    `json:"height"`
+	Models  []ContractModelInfo `json:"models"`
+}
+
+// Contracts returns the contract accounts ordered by address. When model is
+// given, only the contracts which registered the design of the model are
+// returned. With height, the states after the height are ignored.
+func Contracts(
+	st *currencydigest.Database,
+	model, offset string,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+) ([]ContractInfo, error) {
+	stages := contractPageStages(offset, reverse, limit)
+
+	var addresses []string
+	if len(model) > 0 {
+		heights, err := contractModelHeights(st, model, contractHeightMatch(height), stages)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range heights {
+			addresses = append(addresses, heights[i].address)
+		}
+	}
+
+	match := contractHeightMatch(height)
+	if len(model) > 0 {
+		if len(addresses) < 1 {
+			return nil, nil
+		}
+
+		match = append(match, bson.E{Key: "address", Value: bson.D{{"$in", addresses}}})
+		stages = mongo.Pipeline{{{"$sort", bson.D{{"_id", contractSortOrder(reverse)}}}}}
+	}
+
+	contracts, err := contractAccounts(st, match, stages)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contracts) < 1 {
+		return contracts, nil
+	}
+
+	if err := fillContractModels(st, contracts, height); err != nil {
+		return nil, err
+	}
+
+	return contracts, nil
+}
+
+// CountContracts returns the number of the contracts of Contracts.
+func CountContracts(st *currencydigest.Database, model string, height *mitumbase.Height) (int64, error) {
+	if len(model) < 1 {
+		return countGroups(st, defaultColNameContractAccount, contractHeightMatch(height), "address")
+	}
+
+	m := contractModels[model]
+
+	return countGroups(st, m.col, append(contractHeightMatch(height), m.filter...), "contract")
+}
+
+func contractHeightMatch(height *mitumbase.Height) bson.D {
+	if height == nil {
+		return bson.D{}
+	}
+
+	return bson.D{{"height", bson.D{{"$lte", *height}}}}
+}
+
+// countGroups returns the number of the distinct values of key in the
+// documents of the collection col.
+func countGroups(st *currencydigest.Database, col string, match bson.D, key string) (int64, error) {
+	cursor, err := st.DatabaseClient().Collection(col).Aggregate(
+		context.Background(),
+		mongo.Pipeline{
+			{{"$match", match}},
+			{{"$group", bson.D{{"_id", "$" + key}}}},
+			{{"$count", "count"}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var docs []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return 0, err
+	}
+
+	if len(docs) < 1 {
+		return 0, nil
+	}
+
+	return docs[0].Count, nil
+}
+
+// fillContractModels sets the models registered on each contract. With
+// height, the models registered after the height are ignored.
+func fillContractModels(st *currencydigest.Database, contracts []ContractInfo, height *mitumbase.Height) error {
+	addresses := make([]string, len(contracts))
+	byAddress := map[string]*ContractInfo{}
+	for i := range contracts {
+		addresses[i] = contracts[i].Address
+		byAddress[contracts[i].Address] = &contracts[i]
+	}
+
+	for _, name := range contractModelNames {
+		heights, err := contractModelHeights(
+			st, name, append(contractHeightMatch(height), bson.E{Key: "contract", Value: bson.D{{"$in", addresses}}}), nil)
+		if err != nil {
+			return err
+		}
+
+		for i := range heights {
+			if c, found := byAddress[heights[i].address]; found {
+				c.Models = append(c.Models, ContractModelInfo{Model: name, Height: heights[i].height})
+			}
+		}
+	}
+
+	return nil
+}
+
+func contractSortOrder(reverse bool) int {
+	if reverse {
+		return -1
+	}
+
+	return 1
+}
+
+func contractPageStages(offset string, reverse bool, limit int64) mongo.Pipeline {
+	var stages mongo.Pipeline
+
+	if len(offset) > 0 {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+
+		stages = append(stages, bson.D{{"$match", bson.D{{"_id", bson.D{{op, offset}}}}}})
+	}
+
+	stages = append(stages, bson.D{{"$sort", bson.D{{"_id", contractSortOrder(reverse)}}}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		stages = append(stages, bson.D{{"$limit", maxLimit}})
+	default:
+		stages = append(stages, bson.D{{"$limit", limit}})
+	}
+
+	return stages
+}
+
+type contractModelHeight struct {
+	address string
+	height  mitumbase.Height
+}
+
+// contractModelHeights returns the contracts which have the design of the
+// model with the height the design was registered first.
+func contractModelHeights(
+	st *currencydigest.Database,
+	model string,
+	match bson.D,
+	stages mongo.Pipeline,
+) ([]contractModelHeight, error) {
+	m := contractModels[model]
+
+	match = append(match, m.filter...)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+		{{"$group", bson.D{
+			{"_id", "$contract"},
+			{"height", bson.D{{"$min", "$height"}}},
+		}}},
+	}
+	pipeline = append(pipeline, stages...)
+
+	cursor, err := st.DatabaseClient().Collection(m.col).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		Address string           `bson:"_id"`
+		Height  mitumbase.Height `bson:"height"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	heights := make([]contractModelHeight, len(docs))
+	for i := range docs {
+		heights[i] = contractModelHeight{address: docs[i].Address, height: docs[i].Height}
+	}
+
+	return heights, nil
+}
+
+// contractAccounts returns the latest status of the contract accounts with
+// the height the account was created.
+func contractAccounts(st *currencydigest.Database, match bson.D, stages mongo.Pipeline) ([]ContractInfo, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+		{{"$sort", bson.D{{"height", -1}}}},
+		{{"$group", bson.D{
+			{"_id", "$address"},
+			{"doc", bson.D{{"$first", "$$ROOT"}}},
+			{"created", bson.D{{"$min", "$height"}}},
+		}}},
+	}
+
+	pipeline = append(pipeline, stages...)
+	pipeline = append(pipeline, bson.D{{"$replaceRoot", bson.D{{"newRoot", bson.D{
+		{"$mergeObjects", bson.A{"$doc", bson.D{{"created", "$created"}}}},
+	}}}}})
+
+	cursor, err := st.DatabaseClient().Collection(defaultColNameContractAccount).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	var contracts []ContractInfo
+	for cursor.Next(context.Background()) {
+		var doc struct {
+			Address string           `bson:"address"`
+			Created mitumbase.Height `bson:"created"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := extension.StateContractAccountValue(sta)
+		if err != nil {
+			return nil, err
+		}
+
+		contracts = append(contracts, ContractInfo{
+			Address: doc.Address,
+			Owner:   status.Owner().String(),
+			Active:  status.IsActive(),
+			Height:  doc.Created,
+			Models:  []ContractModelInfo{},
+		})
+	}
+
+	return contracts, cursor.Err()
+}
@@ -1,12 +1,21 @@
 package digest
 
 import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
 	"github.com/ProtoconNet/mitum-dao/state"
 	"github.com/ProtoconNet/mitum-dao/types"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -174,3 +183,761 @@ func DAOVotingPowerBox(st *currencydigest.Database, contract, proposalID string)
 
 	return &votingPowerBox, nil
 }
+
+// DAOVotingPowerBoxes returns the latest voting power box of each proposal.
+// The proposals without voting power box are not in the result. With height,
+// the states after the height are ignored.
+func DAOVotingPowerBoxes(
+	st *currencydigest.Database,
+	contract string,
+	proposalIDs []string,
+	height *mitumbase.Height,
+) (map[string]types.VotingPowerBox, error) {
+	boxes := map[string]types.VotingPowerBox{}
+	if len(proposalIDs) < 1 {
+		return boxes, nil
+	}
+
+	match := bson.D{{"contract", contract}, {"proposal_id", bson.D{{"$in", proposalIDs}}}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	if err := daoLatestStates(st, defaultColNameDAOVotingPowerBox, match, nil,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			vpb, err := state.StateVotingPowerBoxValue(sta)
+			if err != nil {
+				return false, err
+			}
+			boxes[proposalID] = vpb
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return boxes, nil
+}
+
+// DAOSnapshotHeights returns the height where the voting power box of each
+// proposal was made first, that is, the pre-snapshot. The proposals which are
+// not snapped yet are not in the result.
+func DAOSnapshotHeights(
+	st *currencydigest.Database, contract string, proposalIDs []string,
+) (map[string]mitumbase.Height, error) {
+	heights := map[string]mitumbase.Height{}
+	if len(proposalIDs) < 1 {
+		return heights, nil
+	}
+
+	cursor, err := st.DatabaseClient().Collection(defaultColNameDAOVotingPowerBox).Aggregate(
+		context.Background(),
+		mongo.Pipeline{
+			{{"$match", bson.D{{"contract", contract}, {"proposal_id", bson.D{{"$in", proposalIDs}}}}}},
+			{{"$group", bson.D{{"_id", "$proposal_id"}, {"height", bson.D{{"$min", "$height"}}}}}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	for cursor.Next(context.Background()) {
+		var doc struct {
+			ProposalID string `bson:"_id"`
+			Height     int64  `bson:"height"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		heights[doc.ProposalID] = mitumbase.Height(doc.Height)
+	}
+
+	return heights, cursor.Err()
+}
+
+var (
+	DAOPeriodNone           = "none"
+	DAOPeriodReview         = "review"
+	DAOPeriodRegistration   = "registration"
+	DAOPeriodPreSnapshot    = "pre-snapshot"
+	DAOPeriodVoting         = "voting"
+	DAOPeriodPostSnapshot   = "post-snapshot"
+	DAOPeriodExecutionDelay = "execution-delay"
+	DAOPeriodExecute        = "execute"
+)
+
+var (
+	DAOProposalStatusPreSnapshot = "pre-snapshot"
+	DAOProposalStatusVoting      = "voting"
+	DAOProposalStatusResult      = "result"
+	DAOProposalStatusExecuted    = "executed"
+	DAOProposalStatusCanceled    = "canceled"
+)
+
+// IsDAOProposalStatus reports whether s is a known proposal status.
+func IsDAOProposalStatus(s string) bool {
+	switch s {
+	case DAOProposalStatusPreSnapshot,
+		DAOProposalStatusVoting,
+		DAOProposalStatusResult,
+		DAOProposalStatusExecuted,
+		DAOProposalStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+type DAOPeriod struct {
+	Name  string `json:"name"`
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end,omitempty"`
+}
+
+// DAOPeriods returns the periods of a proposal started at start, in unix
+// seconds, under policy. The last execute period has no end.
+func DAOPeriods(policy types.Policy, start uint64) []DAOPeriod {
+	return daoPeriods(start, [6]uint64{
+		policy.ProposalReviewPeriod(),
+		policy.RegistrationPeriod(),
+		policy.PreSnapshotPeriod(),
+		policy.VotingPeriod(),
+		policy.PostSnapshotPeriod(),
+		policy.ExecutionDelayPeriod(),
+	})
+}
+
+var daoPeriodNames = [6]string{
+	DAOPeriodReview,
+	DAOPeriodRegistration,
+	DAOPeriodPreSnapshot,
+	DAOPeriodVoting,
+	DAOPeriodPostSnapshot,
+	DAOPeriodExecutionDelay,
+}
+
+// daoPeriods returns the periods of the durations in the order of
+// daoPeriodNames followed by the execute period.
+func daoPeriods(start uint64, durations [6]uint64) []DAOPeriod {
+	periods := make([]DAOPeriod, len(durations)+1)
+
+	t := start
+	for i := range durations {
+		periods[i] = DAOPeriod{Name: daoPeriodNames[i], Start: t, End: t + durations[i]}
+		t += durations[i]
+	}
+	periods[len(durations)] = DAOPeriod{Name: DAOPeriodExecute, Start: t}
+
+	return periods
+}
+
+// DAOCurrentPeriod returns the name of the period which contains now.
+// DAOPeriodNone is returned before the first period starts.
+func DAOCurrentPeriod(periods []DAOPeriod, now time.Time) string {
+	t := uint64(now.Unix())
+
+	for i := range periods {
+		switch {
+		case t < periods[i].Start:
+			return DAOPeriodNone
+		case periods[i].End == 0, t < periods[i].End:
+			return periods[i].Name
+		}
+	}
+
+	return DAOPeriodNone
+}
+
+// DAOProposalLifecycle returns the status of the proposal at the given block
+// time. The stored proposal status takes precedence over the periods, which
+// only move when an operation touches the proposal.
+func DAOProposalLifecycle(policy types.Policy, proposal state.ProposalStateValue, now time.Time) string {
+	switch proposal.Status() {
+	case types.Canceled:
+		return DAOProposalStatusCanceled
+	case types.Executed:
+		return DAOProposalStatusExecuted
+	case types.PostSnapped, types.Completed, types.Rejected:
+		return DAOProposalStatusResult
+	}
+
+	switch DAOCurrentPeriod(DAOPeriods(policy, proposal.Proposal().StartTime()), now) {
+	case DAOPeriodVoting:
+		return DAOProposalStatusVoting
+	case DAOPeriodPostSnapshot, DAOPeriodExecutionDelay, DAOPeriodExecute:
+		return DAOProposalStatusResult
+	default:
+		return DAOProposalStatusPreSnapshot
+	}
+}
+
+type DAOTallySummary struct {
+	VotingPowerTotal common.Big           `json:"voting_power_total"`
+	VotedTotal       common.Big           `json:"voted_total"`
+	Votes            int                  `json:"votes"`
+	Result           map[uint8]common.Big `json:"result"`
+}
+
+// NewDAOTallySummary counts the votes cast in the voting power box. The
+// result is counted from the voting powers, so it is also available before
+// the post snapshot.
+func NewDAOTallySummary(vpb types.VotingPowerBox) DAOTallySummary {
+	summary := DAOTallySummary{
+		VotingPowerTotal: vpb.Total(),
+		VotedTotal:       common.ZeroBig,
+		Result:           map[uint8]common.Big{},
+	}
+
+	for _, vp := range vpb.VotingPowers() {
+		if !vp.Voted() {
+			continue
+		}
+
+		summary.Votes++
+		summary.VotedTotal = summary.VotedTotal.Add(vp.Amount())
+
+		if r, found := summary.Result[vp.VoteFor()]; found {
+			summary.Result[vp.VoteFor()] = r.Add(vp.Amount())
+		} else {
+			summary.Result[vp.VoteFor()] = vp.Amount()
+		}
+	}
+
+	return summary
+}
+
+var (
+	DAOTallyOutcomePassed   = "passed"
+	DAOTallyOutcomeRejected = "rejected"
+	DAOTallyOutcomeCanceled = "canceled"
+)
+
+type DAOVoterTally struct {
+	Account     string     `json:"account"`
+	Voted       bool       `json:"voted"`
+	VoteFor     *uint8     `json:"vote_for,omitempty"`
+	VotingPower common.Big `json:"voting_power"`
+	Delegators  []string   `json:"delegators,omitempty"`
+}
+
+type DAOTally struct {
+	DAOTallySummary
+	Supply         common.Big      `json:"supply"`
+	Turnout        uint8           `json:"turnout"`
+	TurnoutCount   common.Big      `json:"turnout_count"`
+	TurnoutReached bool            `json:"turnout_reached"`
+	Quorum         uint8           `json:"quorum"`
+	QuorumCount    common.Big      `json:"quorum_count"`
+	QuorumReached  bool            `json:"quorum_reached"`
+	Winner         *uint8          `json:"winner,omitempty"`
+	Outcome        string          `json:"outcome"`
+	Final          bool            `json:"final"`
+	Voters         []DAOVoterTally `json:"voters"`
+}
+
+// NewDAOTally counts the votes of the proposal under the policy of the DAO.
+// The turnout compares the voted power with the turnout ratio of the supply
+// of the voting power token, and the quorum compares the votes of the
+// winning option with the quorum ratio of the voted power. A crypto proposal
+// passes only by the votes for the first option, approve; a biz proposal
+// passes by the option with the most votes. The outcome is final once the
+// proposal is post snapped; before that it is the outcome if the voting ended
+// now.
+func NewDAOTally(
+	policy types.Policy,
+	proposal state.ProposalStateValue,
+	vpb types.VotingPowerBox,
+	voters []types.VoterInfo,
+	supply common.Big,
+) DAOTally {
+	summary := NewDAOTallySummary(vpb)
+
+	tally := DAOTally{
+		DAOTallySummary: summary,
+		Supply:          supply,
+		Turnout:         uint8(policy.Turnout()),
+		Quorum:          uint8(policy.Quorum()),
+	}
+
+	tally.count(string(proposal.Proposal().Option()) == string(types.ProposalCrypto))
+
+	switch proposal.Status() {
+	case types.Canceled:
+		tally.Outcome = DAOTallyOutcomeCanceled
+		tally.Final = true
+	case types.Completed, types.Executed:
+		tally.Outcome = DAOTallyOutcomePassed
+		tally.Final = true
+	case types.Rejected:
+		tally.Outcome = DAOTallyOutcomeRejected
+		tally.Final = true
+	default:
+		tally.Outcome = DAOTallyOutcomeRejected
+		if tally.TurnoutReached && tally.QuorumReached {
+			tally.Outcome = DAOTallyOutcomePassed
+		}
+	}
+
+	delegators := map[string][]string{}
+	for i := range voters {
+		ds := voters[i].Delegators()
+		as := make([]string, len(ds))
+		for j := range ds {
+			as[j] = ds[j].String()
+		}
+		delegators[voters[i].Account().String()] = as
+	}
+
+	for _, vp := range vpb.VotingPowers() {
+		account := vp.Account().String()
+
+		v := DAOVoterTally{
+			Account:     account,
+			Voted:       vp.Voted(),
+			VotingPower: vp.Amount(),
+			Delegators:  delegators[account],
+		}
+		if vp.Voted() {
+			voteFor := vp.VoteFor()
+			v.VoteFor = &voteFor
+		}
+
+		tally.Voters = append(tally.Voters, v)
+	}
+
+	sort.Slice(tally.Voters, func(i, j int) bool {
+		return tally.Voters[i].Account < tally.Voters[j].Account
+	})
+
+	return tally
+}
+
+// DAOProposalTally builds the tally of the proposal from the latest voting
+// power box.
+func DAOProposalTally(st *currencydigest.Database, contract, proposalID string) (*DAOTally, error) {
+	design, err := DAOService(st, contract)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "dao service, contract %s", contract)
+	}
+
+	proposal, err := DAOProposal(st, contract, proposalID)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "proposal, contract %s, proposalID %s", contract, proposalID)
+	}
+
+	vpb, err := DAOVotingPowerBox(st, contract, proposalID)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(
+			err, "voting power box, contract %s, proposalID %s", contract, proposalID)
+	}
+
+	// NOTE voters are registered before the pre snapshot; a proposal without
+	// registered voters has no delegations.
+	voters, err := DAOVoters(st, contract, proposalID)
+	if err != nil && !errors.Is(err, mitumutil.ErrNotFound) {
+		return nil, err
+	}
+
+	cid := design.Policy().VotingPowerToken().String()
+	currency, err := CurrencyDesign(st, cid)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "currency, %s", cid)
+	}
+
+	tally := NewDAOTally(design.Policy(), *proposal, *vpb, voters, currency.Aggregate())
+
+	return &tally, nil
+}
+
+// count sets the turnout and quorum counts, the winner and whether the
+// turnout and quorum are reached from the summary and the supply.
+func (tally *DAOTally) count(crypto bool) {
+	summary := tally.DAOTallySummary
+
+	tally.TurnoutCount = daoRatioOf(tally.Supply, tally.Turnout)
+	tally.TurnoutReached = summary.VotedTotal.Compare(tally.TurnoutCount) >= 0
+	tally.QuorumCount = daoRatioOf(summary.VotedTotal, tally.Quorum)
+
+	if crypto {
+		if _, found := summary.Result[0]; found {
+			var i uint8
+			tally.Winner = &i
+		}
+	} else {
+		tally.Winner = daoTopOption(summary.Result)
+	}
+
+	if tally.Winner != nil {
+		tally.QuorumReached = summary.Result[*tally.Winner].Compare(tally.QuorumCount) >= 0
+	}
+}
+
+func daoRatioOf(v common.Big, ratio uint8) common.Big {
+	i := new(big.Int).Mul(v.Int, big.NewInt(int64(ratio)))
+
+	return common.NewBigFromBigInt(i.Div(i, big.NewInt(100)))
+}
+
+// daoTopOption returns the option with the most votes. nil is returned when
+// there are no votes or the top options are tied.
+func daoTopOption(result map[uint8]common.Big) *uint8 {
+	var top *uint8
+	var tied bool
+
+	for option, count := range result {
+		switch {
+		case top == nil:
+		case count.Compare(result[*top]) > 0:
+		case count.Compare(result[*top]) == 0:
+			tied = true
+
+			continue
+		default:
+			continue
+		}
+
+		o := option
+		top = &o
+		tied = false
+	}
+
+	if tied {
+		return nil
+	}
+
+	return top
+}
+
+// DAOProposals calls callback with the latest state of each proposal of the
+// contract ordered by the length of proposal id and then proposal id, so the
+// numeric ids are in numeric order. The proposer and option filters are
+// ignored when empty. With height, the states after the height are ignored.
+func DAOProposals(
+	st *currencydigest.Database,
+	contract, proposer, option, offset string,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+	callback func(proposalID string, proposal state.ProposalStateValue) (bool, error),
+) error {
+	match := daoProposalsMatch(contract, proposer, option, height)
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	stages := mongo.Pipeline{
+		{{"$addFields", bson.D{{"id_len", bson.D{{"$strLenCP", "$_id"}}}}}},
+	}
+
+	if len(offset) > 0 {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+
+		stages = append(stages, bson.D{{"$match", bson.D{{"$or", bson.A{
+			bson.D{{"id_len", bson.D{{op, len([]rune(offset))}}}},
+			bson.D{{"id_len", len([]rune(offset))}, {"_id", bson.D{{op, offset}}}},
+		}}}}})
+	}
+
+	stages = append(stages, bson.D{{"$sort", bson.D{{"id_len", sr}, {"_id", sr}}}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		stages = append(stages, bson.D{{"$limit", maxLimit}})
+	default:
+		stages = append(stages, bson.D{{"$limit", limit}})
+	}
+
+	return daoLatestStates(st, defaultColNameDAOProposal, match, stages,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			proposal, err := state.StateProposalValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(proposalID, proposal)
+		},
+	)
+}
+
+// DAOProposalCount returns the number of the proposals of DAOProposals.
+func DAOProposalCount(
+	st *currencydigest.Database,
+	contract, proposer, option string,
+	height *mitumbase.Height,
+) (int64, error) {
+	return countGroups(st, defaultColNameDAOProposal, daoProposalsMatch(contract, proposer, option, height), "proposal_id")
+}
+
+func daoProposalsMatch(contract, proposer, option string, height *mitumbase.Height) bson.D {
+	match := bson.D{{"contract", contract}}
+	if len(proposer) > 0 {
+		match = append(match, bson.E{Key: "proposer", Value: proposer})
+	}
+	if len(option) > 0 {
+		match = append(match, bson.E{Key: "option", Value: option})
+	}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	return match
+}
+
+// lessProposalID orders the proposal ids by the length and then by the id,
+// like the sort of DAOProposals, so the numeric ids are in numeric order.
+func lessProposalID(a, b string) bool {
+	if la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b); la != lb {
+		return la < lb
+	}
+
+	return a < b
+}
+
+// daoLatestStates calls callback with the latest state of each proposal in
+// the collection col. The stages are applied to the documents grouped by
+// proposal id, before the latest documents are restored.
+func daoLatestStates(
+	st *currencydigest.Database,
+	col string,
+	match bson.D,
+	stages mongo.Pipeline,
+	callback func(proposalID string, sta mitumbase.State) (bool, error),
+) error {
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+		{{"$sort", bson.D{{"height", -1}}}},
+		{{"$group", bson.D{
+			{"_id", "$proposal_id"},
+			{"doc", bson.D{{"$first", "$$ROOT"}}},
+		}}},
+	}
+
+	pipeline = append(pipeline, stages...)
+	pipeline = append(pipeline, bson.D{{"$replaceRoot", bson.D{{"newRoot", "$doc"}}}})
+
+	cursor, err := st.DatabaseClient().Collection(col).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	for cursor.Next(context.Background()) {
+		var doc struct {
+			ProposalID string `bson:"proposal_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+		if err != nil {
+			return err
+		}
+
+		switch keep, err := callback(doc.ProposalID, sta); {
+		case err != nil:
+			return err
+		case !keep:
+			return nil
+		}
+	}
+
+	return cursor.Err()
+}
+
+type DAOAccountGovernance struct {
+	ProposalID  string      `json:"proposal_id"`
+	Voted       bool        `json:"voted"`
+	VoteFor     *uint8      `json:"vote_for,omitempty"`
+	VotingPower *common.Big `json:"voting_power,omitempty"`
+	DelegatedTo string      `json:"delegated_to,omitempty"`
+	Delegators  []string    `json:"delegators,omitempty"`
+}
+
+// DAOAccountGovernances returns the proposals of the contract which the
+// account registered for, voted on or delegated for, ordered by proposal id
+// like DAOProposals.
+// With height, the states after the height are ignored.
+func DAOAccountGovernances(
+	st *currencydigest.Database,
+	contract, account string,
+	height *mitumbase.Height,
+) ([]DAOAccountGovernance, error) {
+	entries := map[string]*DAOAccountGovernance{}
+
+	entry := func(proposalID string) *DAOAccountGovernance {
+		if i, found := entries[proposalID]; found {
+			return i
+		}
+
+		i := &DAOAccountGovernance{ProposalID: proposalID}
+		entries[proposalID] = i
+
+		return i
+	}
+
+	match := bson.D{{"contract", contract}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	if err := daoLatestStates(st, defaultColNameDAODelegators, match, nil,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			delegators, err := state.StateDelegatorsValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			for i := range delegators {
+				if delegators[i].Account().String() == account {
+					entry(proposalID).DelegatedTo = delegators[i].Delegatee().String()
+				}
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := daoLatestStates(st, defaultColNameDAOVoters, match, nil,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			voters, err := state.StateVotersValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			for i := range voters {
+				if voters[i].Account().String() != account {
+					continue
+				}
+
+				e := entry(proposalID)
+				ds := voters[i].Delegators()
+				e.Delegators = make([]string, len(ds))
+				for j := range ds {
+					e.Delegators[j] = ds[j].String()
+				}
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := daoLatestStates(st, defaultColNameDAOVotingPowerBox, match, nil,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			vpb, err := state.StateVotingPowerBoxValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			vp, found := vpb.VotingPowers()[account]
+			if !found {
+				return true, nil
+			}
+
+			e := entry(proposalID)
+			amount := vp.Amount()
+			e.VotingPower = &amount
+			e.Voted = vp.Voted()
+			if vp.Voted() {
+				voteFor := vp.VoteFor()
+				e.VoteFor = &voteFor
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	governances := make([]DAOAccountGovernance, 0, len(entries))
+	for _, i := range entries {
+		governances = append(governances, *i)
+	}
+
+	sort.Slice(governances, func(i, j int) bool {
+		return lessProposalID(governances[i].ProposalID, governances[j].ProposalID)
+	})
+
+	return governances, nil
+}
+
+type DAODelegation struct {
+	ProposalID string      `json:"proposal_id"`
+	Delegator  string      `json:"delegator"`
+	Delegatee  string      `json:"delegatee"`
+	Weight     *common.Big `json:"weight,omitempty"`
+}
+
+// DAODelegations returns the delegations of the given proposals of the
+// contract ordered by proposal id, like DAOProposals, and delegator. With height, the states after
+// the height are ignored.
+func DAODelegations(
+	st *currencydigest.Database,
+	contract string,
+	proposalIDs []string,
+	height *mitumbase.Height,
+) ([]DAODelegation, error) {
+	var delegations []DAODelegation
+
+	if len(proposalIDs) < 1 {
+		return nil, nil
+	}
+
+	match := bson.D{{"contract", contract}, {"proposal_id", bson.D{{"$in", proposalIDs}}}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	if err := daoLatestStates(st, defaultColNameDAODelegators, match, nil,
+		func(proposalID string, sta mitumbase.State) (bool, error) {
+			delegators, err := state.StateDelegatorsValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			for i := range delegators {
+				delegations = append(delegations, DAODelegation{
+					ProposalID: proposalID,
+					Delegator:  delegators[i].Account().String(),
+					Delegatee:  delegators[i].Delegatee().String(),
+				})
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(delegations, func(i, j int) bool {
+		if delegations[i].ProposalID != delegations[j].ProposalID {
+			return lessProposalID(delegations[i].ProposalID, delegations[j].ProposalID)
+		}
+
+		return delegations[i].Delegator < delegations[j].Delegator
+	})
+
+	return delegations, nil
+}
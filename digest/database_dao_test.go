@@ -0,0 +1,227 @@
+package digest
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+)
+
+func TestDAOTopOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		result map[uint8]common.Big
+		top    *uint8
+	}{
+		{name: "empty", result: map[uint8]common.Big{}},
+		{name: "single", result: map[uint8]common.Big{2: common.NewBig(1)}, top: daoOption(2)},
+		{
+			name:   "most",
+			result: map[uint8]common.Big{0: common.NewBig(3), 1: common.NewBig(7), 2: common.NewBig(5)},
+			top:    daoOption(1),
+		},
+		{name: "tied", result: map[uint8]common.Big{0: common.NewBig(7), 1: common.NewBig(7)}},
+		{
+			name:   "tied under top",
+			result: map[uint8]common.Big{0: common.NewBig(3), 1: common.NewBig(3), 2: common.NewBig(9)},
+			top:    daoOption(2),
+		},
+		{
+			name:   "tied top with lower",
+			result: map[uint8]common.Big{0: common.NewBig(9), 1: common.NewBig(3), 2: common.NewBig(9)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// NOTE the map order is random; repeat to catch order dependent
+			// results.
+			for i := 0; i < 30; i++ {
+				top := daoTopOption(c.result)
+
+				switch {
+				case c.top == nil && top != nil:
+					t.Fatalf("expected no top, but %d", *top)
+				case c.top != nil && top == nil:
+					t.Fatalf("expected %d, but no top", *c.top)
+				case c.top != nil && *c.top != *top:
+					t.Fatalf("expected %d, but %d", *c.top, *top)
+				}
+			}
+		})
+	}
+}
+
+func TestDAOTallyCount(t *testing.T) {
+	cases := []struct {
+		name           string
+		crypto         bool
+		result         map[uint8]common.Big
+		supply         int64
+		turnout        uint8
+		quorum         uint8
+		winner         *uint8
+		turnoutReached bool
+		quorumReached  bool
+	}{
+		{
+			name:   "no votes",
+			result: map[uint8]common.Big{}, supply: 100, turnout: 10, quorum: 50,
+		},
+		{
+			name:   "crypto approve",
+			crypto: true,
+			result: map[uint8]common.Big{0: common.NewBig(30), 1: common.NewBig(10)}, supply: 100, turnout: 40, quorum: 75,
+			winner: daoOption(0), turnoutReached: true, quorumReached: true,
+		},
+		{
+			name:   "crypto approve not most",
+			crypto: true,
+			result: map[uint8]common.Big{0: common.NewBig(10), 1: common.NewBig(30)}, supply: 100, turnout: 40, quorum: 50,
+			winner: daoOption(0), turnoutReached: true,
+		},
+		{
+			name:   "crypto no approve",
+			crypto: true,
+			result: map[uint8]common.Big{1: common.NewBig(30)}, supply: 100, turnout: 10, quorum: 50,
+			turnoutReached: true,
+		},
+		{
+			name:   "biz most",
+			result: map[uint8]common.Big{0: common.NewBig(10), 2: common.NewBig(30)}, supply: 100, turnout: 40, quorum: 75,
+			winner: daoOption(2), turnoutReached: true, quorumReached: true,
+		},
+		{
+			name:   "biz tied",
+			result: map[uint8]common.Big{0: common.NewBig(20), 1: common.NewBig(20)}, supply: 100, turnout: 40, quorum: 50,
+			turnoutReached: true,
+		},
+		{
+			name:   "turnout edge",
+			result: map[uint8]common.Big{0: common.NewBig(39)}, supply: 100, turnout: 40, quorum: 50,
+			winner: daoOption(0), quorumReached: true,
+		},
+		{
+			name:   "turnout rounds down",
+			result: map[uint8]common.Big{0: common.NewBig(3)}, supply: 9, turnout: 40, quorum: 50,
+			winner: daoOption(0), turnoutReached: true, quorumReached: true,
+		},
+		{
+			name:   "quorum edge",
+			result: map[uint8]common.Big{0: common.NewBig(49), 1: common.NewBig(51)}, supply: 100, turnout: 100, quorum: 52,
+			winner: daoOption(1), turnoutReached: true,
+		},
+		{
+			name:   "zero supply",
+			result: map[uint8]common.Big{}, supply: 0, turnout: 10, quorum: 50,
+			turnoutReached: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			voted := common.ZeroBig
+			for _, v := range c.result {
+				voted = voted.Add(v)
+			}
+
+			tally := DAOTally{
+				DAOTallySummary: DAOTallySummary{VotedTotal: voted, Result: c.result},
+				Supply:          common.NewBig(c.supply),
+				Turnout:         c.turnout,
+				Quorum:          c.quorum,
+			}
+			tally.count(c.crypto)
+
+			switch {
+			case c.winner == nil && tally.Winner != nil:
+				t.Errorf("expected no winner, but %d", *tally.Winner)
+			case c.winner != nil && tally.Winner == nil:
+				t.Errorf("expected winner %d, but none", *c.winner)
+			case c.winner != nil && *c.winner != *tally.Winner:
+				t.Errorf("expected winner %d, but %d", *c.winner, *tally.Winner)
+			}
+
+			if tally.TurnoutReached != c.turnoutReached {
+				t.Errorf("expected turnout reached %v, but %v", c.turnoutReached, tally.TurnoutReached)
+			}
+
+			if tally.QuorumReached != c.quorumReached {
+				t.Errorf("expected quorum reached %v, but %v", c.quorumReached, tally.QuorumReached)
+			}
+		})
+	}
+}
+
+func TestDAOPeriods(t *testing.T) {
+	periods := daoPeriods(100, [6]uint64{10, 20, 0, 30, 5, 15})
+
+	expected := []DAOPeriod{
+		{Name: DAOPeriodReview, Start: 100, End: 110},
+		{Name: DAOPeriodRegistration, Start: 110, End: 130},
+		{Name: DAOPeriodPreSnapshot, Start: 130, End: 130},
+		{Name: DAOPeriodVoting, Start: 130, End: 160},
+		{Name: DAOPeriodPostSnapshot, Start: 160, End: 165},
+		{Name: DAOPeriodExecutionDelay, Start: 165, End: 180},
+		{Name: DAOPeriodExecute, Start: 180},
+	}
+
+	if len(periods) != len(expected) {
+		t.Fatalf("expected %d periods, but %d", len(expected), len(periods))
+	}
+
+	for i := range expected {
+		if periods[i] != expected[i] {
+			t.Errorf("%d: expected %+v, but %+v", i, expected[i], periods[i])
+		}
+	}
+}
+
+func TestDAOCurrentPeriod(t *testing.T) {
+	periods := daoPeriods(100, [6]uint64{10, 20, 0, 30, 5, 15})
+
+	cases := []struct {
+		now      int64
+		expected string
+	}{
+		{now: 0, expected: DAOPeriodNone},
+		{now: 99, expected: DAOPeriodNone},
+		{now: 100, expected: DAOPeriodReview},
+		{now: 109, expected: DAOPeriodReview},
+		{now: 110, expected: DAOPeriodRegistration},
+		{now: 130, expected: DAOPeriodVoting}, // NOTE empty pre-snapshot is skipped
+		{now: 159, expected: DAOPeriodVoting},
+		{now: 160, expected: DAOPeriodPostSnapshot},
+		{now: 165, expected: DAOPeriodExecutionDelay},
+		{now: 180, expected: DAOPeriodExecute},
+		{now: 1 << 40, expected: DAOPeriodExecute},
+	}
+
+	for _, c := range cases {
+		if p := DAOCurrentPeriod(periods, time.Unix(c.now, 0)); p != c.expected {
+			t.Errorf("%d: expected %q, but %q", c.now, c.expected, p)
+		}
+	}
+
+	if p := DAOCurrentPeriod(nil, time.Unix(100, 0)); p != DAOPeriodNone {
+		t.Errorf("empty periods: expected %q, but %q", DAOPeriodNone, p)
+	}
+}
+
+func daoOption(i uint8) *uint8 {
+	return &i
+}
+
+func TestLessProposalID(t *testing.T) {
+	ids := []string{"10", "2", "b", "1", "a", "100", "ab"}
+	expected := []string{"1", "2", "a", "b", "10", "ab", "100"}
+
+	sort.Slice(ids, func(i, j int) bool { return lessProposalID(ids[i], ids[j]) })
+
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected %v, but %v", expected, ids)
+		}
+	}
+}
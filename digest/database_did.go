@@ -2,17 +2,89 @@ package digest
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ProtoconNet/mitum-credential/state"
 	"github.com/ProtoconNet/mitum-credential/types"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
+	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	CredentialLifecyclePending = "pending"
+	CredentialLifecycleActive  = "active"
+	CredentialLifecycleExpired = "expired"
+	CredentialLifecycleRevoked = "revoked"
+)
+
+// CredentialLifecycle returns the lifecycle status of credential at the given
+// block time. A valid until of zero means the credential never expires.
+func CredentialLifecycle(credential types.Credential, isActive bool, now time.Time) string {
+	t := uint64(now.Unix())
+
+	switch {
+	case !isActive:
+		return CredentialLifecycleRevoked
+	case credential.ValidFrom() > t:
+		return CredentialLifecyclePending
+	case credential.ValidUntil() > 0 && credential.ValidUntil() <= t:
+		return CredentialLifecycleExpired
+	default:
+		return CredentialLifecycleActive
+	}
+}
+
+// IsCredentialLifecycle reports whether s is a known lifecycle status.
+func IsCredentialLifecycle(s string) bool {
+	switch s {
+	case CredentialLifecyclePending, CredentialLifecycleActive, CredentialLifecycleExpired, CredentialLifecycleRevoked:
+		return true
+	default:
+		return false
+	}
+}
+
+// buildCredentialLifecycleFilter filters by valid_from and valid_until of the
+// documents; the documents stored without them are filled by
+// backfillCredentialLifecycle at start.
+func buildCredentialLifecycleFilter(status string, now time.Time) bson.A {
+	t := now.Unix()
+
+	switch status {
+	case CredentialLifecycleRevoked:
+		return bson.A{bson.D{{"is_active", false}}}
+	case CredentialLifecyclePending:
+		return bson.A{
+			bson.D{{"is_active", true}},
+			bson.D{{"valid_from", bson.D{{"$gt", t}}}},
+		}
+	case CredentialLifecycleExpired:
+		return bson.A{
+			bson.D{{"is_active", true}},
+			bson.D{{"valid_until", bson.D{{"$gt", 0}, {"$lte", t}}}},
+		}
+	case CredentialLifecycleActive:
+		return bson.A{
+			bson.D{{"is_active", true}},
+			bson.D{{"valid_from", bson.D{{"$lte", t}}}},
+			bson.D{{"$or", bson.A{
+				bson.D{{"valid_until", 0}},
+				bson.D{{"valid_until", bson.D{{"$gt", t}}}},
+			}}},
+		}
+	default:
+		return nil
+	}
+}
+
 func CredentialService(st *currencydigest.Database, contract string) (*types.Design, error) {
 	filter := util.NewBSONFilter("contract", contract)
 
@@ -44,8 +116,7 @@ func CredentialService(st *currencydigest.Database, contract string) (*types.Des
 	return design, nil
 }
 
-func Credential(st *currencydigest.Database, contract, templateID, credentialID string) (*types.Credential, bool, error) {
-
+func Credential(st *currencydigest.Database, contract, templateID, credentialID string) (*types.Credential, bool, mitumbase.State, error) {
 	filter := util.NewBSONFilter("contract", contract)
 	filter = filter.Add("template", templateID)
 	filter = filter.Add("credential_id", credentialID)
@@ -72,10 +143,10 @@ func Credential(st *currencydigest.Database, contract, templateID, credentialID
 		},
 		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
 	); err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
-	return credential, isActive, nil
+	return credential, isActive, sta, nil
 }
 
 func Template(st *currencydigest.Database, contract, templateID string) (*types.Template, error) {
@@ -108,6 +179,152 @@ func Template(st *currencydigest.Database, contract, templateID string) (*types.
 	return template, nil
 }
 
+// TemplatesByService calls callback with the latest version of each template
+// registered in the credential service, in order of registration. With height,
+// the templates are as of the height.
+func TemplatesByService(
+	st *currencydigest.Database,
+	contract string,
+	height *mitumbase.Height,
+	callback func(string, types.Template) (bool, error),
+) error {
+	filter := util.NewBSONFilter("contract", contract)
+	if height != nil {
+		filter = filter.Add("height", bson.D{{"$lte", *height}})
+	}
+
+	var templateIDs []string
+	templates := map[string]types.Template{}
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameTemplate,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			parsedKey, err := crcystate.ParseStateKey(sta.Key(), state.CredentialPrefix, 4)
+			if err != nil {
+				return false, err
+			}
+
+			te, err := state.StateTemplateValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			if _, found := templates[parsedKey[2]]; !found {
+				templateIDs = append(templateIDs, parsedKey[2])
+			}
+			templates[parsedKey[2]] = te
+
+			return true, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", 1).D()),
+	); err != nil {
+		return err
+	}
+
+	for _, id := range templateIDs {
+		switch keep, err := callback(id, templates[id]); {
+		case err != nil:
+			return err
+		case !keep:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+type CredentialTemplateStatistics struct {
+	Issued  int64 `json:"issued"`
+	Active  int64 `json:"active"`
+	Revoked int64 `json:"revoked"`
+	Holders int64 `json:"holders"`
+}
+
+// CredentialTemplateStats counts the credentials issued with the template and
+// the distinct holders of them. Active credentials are counted against now.
+func CredentialTemplateStats(
+	st *currencydigest.Database,
+	contract, templateID string,
+	now time.Time,
+) (CredentialTemplateStatistics, error) {
+	var stats CredentialTemplateStatistics
+
+	count := func(lifecycle string) (int64, error) {
+		filterA := bson.A{
+			bson.D{{"contract", contract}},
+			bson.D{{"template", templateID}},
+		}
+		filterA = append(filterA, buildCredentialLifecycleFilter(lifecycle, now)...)
+
+		return st.DatabaseClient().Count(
+			context.Background(),
+			defaultColNameDIDCredential,
+			bson.D{{"$and", filterA}},
+			options.Count(),
+		)
+	}
+
+	var err error
+	if stats.Issued, err = count(""); err != nil {
+		return stats, err
+	}
+
+	if stats.Active, err = count(CredentialLifecycleActive); err != nil {
+		return stats, err
+	}
+
+	// NOTE the credentials without valid_from and valid_until, which are
+	// not backfilled yet, do not match the lifecycle filter; they are
+	// counted by their state.
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameDIDCredential,
+		bson.D{{"contract", contract}, {"template", templateID}, {"valid_until", bson.D{{"$exists", false}}}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			credential, isActive, err := state.StateCredentialValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			if CredentialLifecycle(credential, isActive, now) == CredentialLifecycleActive {
+				stats.Active++
+			}
+
+			return true, nil
+		},
+		options.Find(),
+	); err != nil {
+		return stats, err
+	}
+
+	if stats.Revoked, err = count(CredentialLifecycleRevoked); err != nil {
+		return stats, err
+	}
+
+	holders, err := st.DatabaseClient().Collection(defaultColNameDIDCredential).Distinct(
+		context.Background(),
+		"d.value.credential.holder",
+		bson.D{{"contract", contract}, {"template", templateID}},
+	)
+	if err != nil {
+		return stats, err
+	}
+	stats.Holders = int64(len(holders))
+
+	return stats, nil
+}
+
 func HolderDID(st *currencydigest.Database, contract, holder string) (string, error) {
 	filter := util.NewBSONFilter("contract", contract)
 	filter = filter.Add("holder", holder)
@@ -145,9 +362,12 @@ func CredentialsByServiceAndTemplate(
 	reverse bool,
 	offset string,
 	limit int64,
+	status string,
+	now time.Time,
+	height *mitumbase.Height,
 	callback func(types.Credential, bool, mitumbase.State) (bool, error),
 ) error {
-	filter, err := buildCredentialFilterByServiceTemplate(contract, templateID, offset, reverse)
+	filter, err := buildCredentialFilterByServiceTemplate(contract, templateID, offset, reverse, status, now, height)
 	if err != nil {
 		return err
 	}
@@ -158,7 +378,7 @@ func CredentialsByServiceAndTemplate(
 	}
 
 	opt := options.Find().SetSort(
-		util.NewBSONFilter("height", sr).D(),
+		util.NewBSONFilter("credential_id", sr).D(),
 	)
 
 	switch {
@@ -189,7 +409,31 @@ func CredentialsByServiceAndTemplate(
 	)
 }
 
-func buildCredentialFilterByServiceTemplate(contract, templateID string, offset string, reverse bool) (bson.D, error) {
+// CredentialCountByServiceAndTemplate returns the number of the credentials
+// of CredentialsByServiceAndTemplate.
+func CredentialCountByServiceAndTemplate(
+	st *currencydigest.Database,
+	contract, templateID string,
+	status string,
+	now time.Time,
+	height *mitumbase.Height,
+) (int64, error) {
+	filter, err := buildCredentialFilterByServiceTemplate(contract, templateID, "", false, status, now, height)
+	if err != nil {
+		return 0, err
+	}
+
+	return st.DatabaseClient().Count(
+		context.Background(),
+		defaultColNameDIDCredential,
+		filter,
+		options.Count(),
+	)
+}
+
+func buildCredentialFilterByServiceTemplate(
+	contract, templateID string, offset string, reverse bool, status string, now time.Time, height *mitumbase.Height,
+) (bson.D, error) {
 	filterA := bson.A{}
 
 	// filter fot matching collection
@@ -213,6 +457,9 @@ func buildCredentialFilterByServiceTemplate(contract, templateID string, offset
 		}
 	}
 
+	filterA = append(filterA, buildCredentialLifecycleFilter(status, now)...)
+	filterA = append(filterA, contractHeightMatch(height))
+
 	filter := bson.D{}
 	if len(filterA) > 0 {
 		filter = bson.D{
@@ -226,9 +473,12 @@ func buildCredentialFilterByServiceTemplate(contract, templateID string, offset
 func CredentialsByServiceHolder(
 	st *currencydigest.Database,
 	contract, holder string,
+	status string,
+	now time.Time,
+	height *mitumbase.Height,
 	callback func(types.Credential, bool, mitumbase.State) (bool, error),
 ) error {
-	filter, err := buildCredentialFilterByServiceHolder(contract, holder)
+	filter, err := buildCredentialFilterByServiceHolder(contract, holder, status, now, height)
 	if err != nil {
 		return err
 	}
@@ -258,7 +508,9 @@ func CredentialsByServiceHolder(
 	)
 }
 
-func buildCredentialFilterByServiceHolder(contract, holder string) (bson.D, error) {
+func buildCredentialFilterByServiceHolder(
+	contract, holder string, status string, now time.Time, height *mitumbase.Height,
+) (bson.D, error) {
 	filterA := bson.A{}
 
 	// filter fot matching collection
@@ -266,6 +518,8 @@ func buildCredentialFilterByServiceHolder(contract, holder string) (bson.D, erro
 	filterHolder := bson.D{{"d.value.credential.holder", holder}}
 	filterA = append(filterA, filterContract)
 	filterA = append(filterA, filterHolder)
+	filterA = append(filterA, buildCredentialLifecycleFilter(status, now)...)
+	filterA = append(filterA, contractHeightMatch(height))
 
 	filter := bson.D{}
 	if len(filterA) > 0 {
@@ -276,3 +530,291 @@ func buildCredentialFilterByServiceHolder(contract, holder string) (bson.D, erro
 
 	return filter, nil
 }
+
+// CredentialExpiringKey is the offset of the credential in the expiring
+// credentials of the template.
+func CredentialExpiringKey(credential types.Credential) string {
+	return strconv.FormatUint(credential.ValidUntil(), 10) + ":" + credential.ID()
+}
+
+// CredentialsExpiring returns the active credentials of the template whose
+// validity ends after now and no later than now plus within, soonest first;
+// the credentials which end at the same time are ordered by the id. offset is
+// the key of CredentialExpiringKey. With height, the credentials updated
+// after the height are excluded; the credentials keep only the latest state.
+func CredentialsExpiring(
+	st *currencydigest.Database,
+	contract, templateID string,
+	now time.Time,
+	within time.Duration,
+	offset string,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+	callback func(types.Credential, bool, mitumbase.State) (bool, error),
+) error {
+	filterA := credentialsExpiringFilter(contract, templateID, now, within, height)
+
+	if len(offset) > 0 {
+		i := strings.Index(offset, ":")
+		if i < 1 {
+			return errors.Errorf("invalid offset, %q", offset)
+		}
+
+		validUntil, err := strconv.ParseInt(offset[:i], 10, 64)
+		if err != nil {
+			return errors.Errorf("invalid offset, %q", offset)
+		}
+
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+
+		filterA = append(filterA, bson.D{{"$or", bson.A{
+			bson.D{{"valid_until", bson.D{{op, validUntil}}}},
+			bson.D{{"valid_until", validUntil}, {"credential_id", bson.D{{op, offset[i+1:]}}}},
+		}}})
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(bson.D{{"valid_until", sr}, {"credential_id", sr}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameDIDCredential,
+		bson.D{{"$and", filterA}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			st, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+			credential, isActive, err := state.StateCredentialValue(st)
+			if err != nil {
+				return false, err
+			}
+			return callback(credential, isActive, st)
+		},
+		opt,
+	)
+}
+
+// CredentialExpiringCount counts the credentials of CredentialsExpiring.
+func CredentialExpiringCount(
+	st *currencydigest.Database,
+	contract, templateID string,
+	now time.Time,
+	within time.Duration,
+	height *mitumbase.Height,
+) (int64, error) {
+	return st.DatabaseClient().Count(
+		context.Background(),
+		defaultColNameDIDCredential,
+		bson.D{{"$and", credentialsExpiringFilter(contract, templateID, now, within, height)}},
+		options.Count(),
+	)
+}
+
+func credentialsExpiringFilter(
+	contract, templateID string, now time.Time, within time.Duration, height *mitumbase.Height,
+) bson.A {
+	return bson.A{
+		bson.D{{"contract", contract}},
+		bson.D{{"template", templateID}},
+		bson.D{{"is_active", true}},
+		bson.D{{"valid_until", bson.D{
+			{"$gt", now.Unix()},
+			{"$lte", now.Add(within).Unix()},
+		}}},
+		contractHeightMatch(height),
+	}
+}
+
+type CredentialStatusList struct {
+	Contract    string `json:"contract"`
+	Template    string `json:"template"`
+	EncodedList string `json:"encoded_list"`
+	Size        uint64 `json:"size"`
+	Revoked     uint64 `json:"revoked"`
+	Height      int64  `json:"height"`
+}
+
+func CredentialStatusIndexes(st *currencydigest.Database, contract, templateID string) (map[string]uint64, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("template", templateID)
+
+	indexes := map[string]uint64{}
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameDIDCredentialStatus,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				CredentialID string `bson:"credential_id"`
+				Index        uint64 `bson:"index"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+			indexes[doc.CredentialID] = doc.Index
+
+			return true, nil
+		},
+		options.Find(),
+	); err != nil {
+		return nil, err
+	}
+
+	return indexes, nil
+}
+
+func CredentialStatusIndex(st *currencydigest.Database, contract, templateID, credentialID string) (uint64, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("template", templateID)
+	filter = filter.Add("credential_id", credentialID)
+
+	var index uint64
+	if err := st.DatabaseClient().GetByFilter(
+		defaultColNameDIDCredentialStatus,
+		filter.D(),
+		func(res *mongo.SingleResult) error {
+			var doc struct {
+				Index uint64 `bson:"index"`
+			}
+			if err := res.Decode(&doc); err != nil {
+				return err
+			}
+			index = doc.Index
+
+			return nil
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// CredentialActives returns whether each credential of the template is active
+// and the highest height of the credentials.
+func CredentialActives(
+	st *currencydigest.Database, contract, templateID string,
+) (map[string]bool, mitumbase.Height, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("template", templateID)
+
+	actives := map[string]bool{}
+	height := mitumbase.NilHeight
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameDIDCredential,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				CredentialID string `bson:"credential_id"`
+				IsActive     bool   `bson:"is_active"`
+				Height       int64  `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+			actives[doc.CredentialID] = doc.IsActive
+
+			if h := mitumbase.Height(doc.Height); h > height {
+				height = h
+			}
+
+			return true, nil
+		},
+		options.Find().SetProjection(bson.D{{"credential_id", 1}, {"is_active", 1}, {"height", 1}}),
+	); err != nil {
+		return nil, mitumbase.NilHeight, err
+	}
+
+	return actives, height, nil
+}
+
+// CredentialTemplateKeys returns the contract and template of every template
+// which has credentials.
+func CredentialTemplateKeys(ctx context.Context, st *currencydigest.Database) ([][2]string, error) {
+	cursor, err := st.DatabaseClient().Collection(defaultColNameDIDCredential).Aggregate(
+		ctx,
+		mongo.Pipeline{
+			{{"$group", bson.D{{"_id", bson.D{{"contract", "$contract"}, {"template", "$template"}}}}}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var keys [][2]string
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID struct {
+				Contract string `bson:"contract"`
+				Template string `bson:"template"`
+			} `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, [2]string{doc.ID.Contract, doc.ID.Template})
+	}
+
+	return keys, cursor.Err()
+}
+
+func CredentialStatusListByTemplate(st *currencydigest.Database, contract, templateID string) (*CredentialStatusList, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("template", templateID)
+
+	var list *CredentialStatusList
+	if err := st.DatabaseClient().GetByFilter(
+		defaultColNameDIDStatusList,
+		filter.D(),
+		func(res *mongo.SingleResult) error {
+			var doc struct {
+				EncodedList string `bson:"encoded_list"`
+				Size        uint64 `bson:"size"`
+				Revoked     uint64 `bson:"revoked"`
+				Height      int64  `bson:"height"`
+			}
+			if err := res.Decode(&doc); err != nil {
+				return err
+			}
+
+			list = &CredentialStatusList{
+				Contract:    contract,
+				Template:    templateID,
+				EncodedList: doc.EncodedList,
+				Size:        doc.Size,
+				Revoked:     doc.Revoked,
+				Height:      doc.Height,
+			}
+
+			return nil
+		},
+		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+	); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
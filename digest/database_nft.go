@@ -78,14 +78,22 @@ func NFT(st *currencydigest.Database, contract, idx string) (*types.NFT, error)
 	return nft, nil
 }
 
+// NFTsByCollection calls callback with the nfts of the collection ordered by
+// id. With height, the nfts minted after the height are excluded.
 func NFTsByCollection(
 	st *currencydigest.Database,
 	contract, factHash, offset string,
 	reverse bool,
 	limit int64,
+	height *mitumbase.Height,
 	callback func(nft types.NFT, st mitumbase.State) (bool, error),
 ) error {
-	filter, err := buildNFTsFilterByContract(contract, factHash, offset, reverse)
+	end, err := nftIndexAt(st, contract, height)
+	if err != nil {
+		return err
+	}
+
+	filter, err := buildNFTsFilterByContract(contract, factHash, offset, reverse, end)
 	if err != nil {
 		return err
 	}
@@ -128,21 +136,17 @@ func NFTsByCollection(
 
 func NFTCountByCollection(
 	st *currencydigest.Database,
-	contract string,
+	contract, factHash string,
+	height *mitumbase.Height,
 ) (int64, error) {
-	filterA := bson.A{}
-
-	// filter fot matching collection
-	filterContract := bson.D{{"contract", bson.D{{"$in", []string{contract}}}}}
-	filterToken := bson.D{{"istoken", true}}
-	filterA = append(filterA, filterToken)
-	filterA = append(filterA, filterContract)
-
-	filter := bson.D{}
-	if len(filterA) > 0 {
-		filter = bson.D{
-			{"$and", filterA},
-		}
+	end, err := nftIndexAt(st, contract, height)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, err := buildNFTsFilterByContract(contract, factHash, "", false, end)
+	if err != nil {
+		return 0, err
 	}
 
 	opt := options.Count()
@@ -155,6 +159,43 @@ func NFTCountByCollection(
 	)
 }
 
+// nftIndexAt returns the last nft index of the collection at the height, the
+// id of the next nft to be minted; the nfts minted until the height have the
+// smaller ids. The nft documents keep only the latest state, but the last index
+// documents are kept for every height. It returns nil without height.
+func nftIndexAt(st *currencydigest.Database, contract string, height *mitumbase.Height) (*uint64, error) {
+	if height == nil {
+		return nil, nil
+	}
+
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("id", bson.D{{"$exists", true}})
+	filter = filter.Add("height", bson.D{{"$lte", *height}})
+
+	var index uint64
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameNFT,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				ID uint64 `bson:"id"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+			index = doc.ID
+
+			return false, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", -1).D()).SetLimit(1),
+	); err != nil {
+		return nil, err
+	}
+
+	return &index, nil
+}
+
 func NFTOperators(
 	st *currencydigest.Database,
 	contract, account string,
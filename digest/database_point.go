@@ -1,6 +1,10 @@
 package digest
 
 import (
+	"context"
+	"sort"
+	"time"
+
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
@@ -8,6 +12,7 @@ import (
 	"github.com/ProtoconNet/mitum-point/types"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -72,3 +77,209 @@ func PointBalance(st *currencydigest.Database, contract, account string) (common
 
 	return amount, nil
 }
+
+type PointRank struct {
+	Rank       int64      `json:"rank"`
+	Address    string     `json:"address"`
+	Earned     common.Big `json:"earned"`
+	Activities int64      `json:"activities"`
+}
+
+// PointLeaderboard ranks the accounts by the points earned, minted or
+// received, from the height fromHeight. With height, the activities after the
+// height are not counted. The amounts are summed here, not by the database, so
+// the large totals are exact.
+func PointLeaderboard(
+	st *currencydigest.Database,
+	contract string,
+	fromHeight *mitumbase.Height,
+	height *mitumbase.Height,
+) ([]PointRank, error) {
+	filter := bson.D{{"contract", contract}, {"earned", true}}
+
+	var heights bson.D
+	if fromHeight != nil {
+		heights = append(heights, bson.E{Key: "$gte", Value: *fromHeight})
+	}
+	if height != nil {
+		heights = append(heights, bson.E{Key: "$lte", Value: *height})
+	}
+	if len(heights) > 0 {
+		filter = append(filter, bson.E{Key: "height", Value: heights})
+	}
+
+	var ranks []PointRank
+	indexes := map[string]int{}
+
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNamePointActivity,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Address string `bson:"address"`
+				Amount  string `bson:"amount"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			amount, err := common.NewBigFromString(doc.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			i, found := indexes[doc.Address]
+			if !found {
+				i = len(ranks)
+				indexes[doc.Address] = i
+				ranks = append(ranks, PointRank{Address: doc.Address, Earned: common.ZeroBig})
+			}
+
+			ranks[i].Earned = ranks[i].Earned.Add(amount)
+			ranks[i].Activities++
+
+			return true, nil
+		},
+		options.Find().SetProjection(bson.D{{"address", 1}, {"amount", 1}}),
+	); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ranks, func(i, j int) bool {
+		if c := ranks[i].Earned.Compare(ranks[j].Earned); c != 0 {
+			return c > 0
+		}
+
+		return ranks[i].Address < ranks[j].Address
+	})
+
+	for i := range ranks {
+		ranks[i].Rank = int64(i + 1)
+	}
+
+	return ranks, nil
+}
+
+type PointActivity struct {
+	Kind         string           `json:"kind"`
+	Counterparty string           `json:"counterparty,omitempty"`
+	Amount       common.Big       `json:"amount"`
+	FactHash     string           `json:"fact_hash"`
+	Height       mitumbase.Height `json:"height"`
+	Index        uint64           `json:"index"`
+	ConfirmedAt  time.Time        `json:"confirmed_at"`
+}
+
+// PointActivityCount returns the number of the activities of the account.
+func PointActivityCount(
+	st *currencydigest.Database,
+	contract, address string,
+	height *mitumbase.Height,
+) (int64, error) {
+	return st.DatabaseClient().Count(
+		context.Background(),
+		defaultColNamePointActivity,
+		bson.D{{"$and", pointActivityFilter(contract, address, height)}},
+		options.Count(),
+	)
+}
+
+func pointActivityFilter(contract, address string, height *mitumbase.Height) bson.A {
+	filterA := bson.A{
+		bson.D{{"contract", contract}},
+		bson.D{{"address", address}},
+	}
+
+	if height != nil {
+		filterA = append(filterA, bson.D{{"height", bson.D{{"$lte", *height}}}})
+	}
+
+	return filterA
+}
+
+func PointActivities(
+	st *currencydigest.Database,
+	contract, address string,
+	offset *PointActivity,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+	callback func(PointActivity) (bool, error),
+) error {
+	filterA := pointActivityFilter(contract, address, height)
+
+	if offset != nil {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+
+		filterA = append(filterA, bson.D{{"$or", bson.A{
+			bson.D{{"height", bson.D{{op, offset.Height}}}},
+			bson.D{{"height", offset.Height}, {"index", bson.D{{op, offset.Index}}}},
+		}}})
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(bson.D{{"height", sr}, {"index", sr}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	times := map[mitumbase.Height]time.Time{}
+
+	return st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNamePointActivity,
+		bson.D{{"$and", filterA}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Kind         string           `bson:"kind"`
+				Counterparty string           `bson:"counterparty"`
+				Amount       string           `bson:"amount"`
+				FactHash     string           `bson:"fact_hash"`
+				Height       mitumbase.Height `bson:"height"`
+				Index        uint64           `bson:"index"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			amount, err := common.NewBigFromString(doc.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			t, found := times[doc.Height]
+			if !found {
+				i, err := BlockTime(st, doc.Height)
+				if err != nil {
+					return false, err
+				}
+				t = i
+				times[doc.Height] = t
+			}
+
+			return callback(PointActivity{
+				Kind:         doc.Kind,
+				Counterparty: doc.Counterparty,
+				Amount:       amount,
+				FactHash:     doc.FactHash,
+				Height:       doc.Height,
+				Index:        doc.Index,
+				ConfirmedAt:  t,
+			})
+		},
+		opt,
+	)
+}
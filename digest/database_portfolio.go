@@ -0,0 +1,287 @@
+package digest
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	credentialstate "github.com/ProtoconNet/mitum-credential/state"
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	currencytypes "github.com/ProtoconNet/mitum-currency/v3/types"
+	daostate "github.com/ProtoconNet/mitum-dao/state"
+	pointstate "github.com/ProtoconNet/mitum-point/state"
+	ststo "github.com/ProtoconNet/mitum-sto/state/sto"
+	tokenstate "github.com/ProtoconNet/mitum-token/state"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var maxPortfolioNFTs int64 = 1000
+
+type PortfolioBalance struct {
+	Contract string     `json:"contract"`
+	Balance  common.Big `json:"balance"`
+}
+
+type PortfolioNFT struct {
+	Contract string `json:"contract"`
+	NFTID    uint64 `json:"nftid"`
+}
+
+type PortfolioSTOBalance struct {
+	Contract  string     `json:"contract"`
+	Partition string     `json:"partition"`
+	Balance   common.Big `json:"balance"`
+}
+
+type PortfolioCredential struct {
+	Contract     string `json:"contract"`
+	Template     string `json:"template"`
+	CredentialID string `json:"credential_id"`
+	Status       string `json:"status"`
+}
+
+type PortfolioDelegation struct {
+	Contract   string `json:"contract"`
+	ProposalID string `json:"proposal_id"`
+	Delegatee  string `json:"delegatee"`
+}
+
+// Portfolio is the holdings of the account. At most maxPortfolioNFTs nfts are
+// listed; NFTsTruncated is set when the account has more.
+type Portfolio struct {
+	Address       string                 `json:"address"`
+	Height        mitumbase.Height       `json:"height"`
+	Currencies    []currencytypes.Amount `json:"currencies"`
+	Tokens        []PortfolioBalance     `json:"tokens"`
+	Points        []PortfolioBalance     `json:"points"`
+	NFTs          []PortfolioNFT         `json:"nfts"`
+	NFTsTruncated bool                   `json:"nfts_truncated,omitempty"`
+	STO           []PortfolioSTOBalance  `json:"sto"`
+	Credentials   []PortfolioCredential  `json:"credentials"`
+	Delegations   []PortfolioDelegation  `json:"delegations"`
+}
+
+// AccountPortfolio collects the latest holdings of the account from the
+// collections of every model. Balances of zero are left out.
+func AccountPortfolio(st *currencydigest.Database, address string, now time.Time) (*Portfolio, error) {
+	portfolio := Portfolio{
+		Address:     address,
+		Height:      st.LastBlock(),
+		Currencies:  []currencytypes.Amount{},
+		Tokens:      []PortfolioBalance{},
+		Points:      []PortfolioBalance{},
+		NFTs:        []PortfolioNFT{},
+		STO:         []PortfolioSTOBalance{},
+		Credentials: []PortfolioCredential{},
+		Delegations: []PortfolioDelegation{},
+	}
+
+	byAddress := bson.D{{"address", address}}
+
+	if err := latestStates(st, defaultColNameBalance, byAddress, []string{"currency"}, nil,
+		func(_ bson.M, sta mitumbase.State) error {
+			amount, err := statecurrency.StateBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if amount.Big().OverZero() {
+				portfolio.Currencies = append(portfolio.Currencies, amount)
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := latestStates(st, defaultColNameTokenBalance, byAddress, []string{"contract"}, nil,
+		func(doc bson.M, sta mitumbase.State) error {
+			amount, err := tokenstate.StateTokenBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if amount.OverZero() {
+				contract, _ := doc["contract"].(string)
+				portfolio.Tokens = append(portfolio.Tokens, PortfolioBalance{Contract: contract, Balance: amount})
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := latestStates(st, defaultColNamePointBalance, byAddress, []string{"contract"}, nil,
+		func(doc bson.M, sta mitumbase.State) error {
+			amount, err := pointstate.StatePointBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if amount.OverZero() {
+				contract, _ := doc["contract"].(string)
+				portfolio.Points = append(portfolio.Points, PortfolioBalance{Contract: contract, Balance: amount})
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := latestStates(st, defaultColNameSTOHolderPartitionBalance, bson.D{{"holder", address}},
+		[]string{"contract", "partition"}, nil,
+		func(doc bson.M, sta mitumbase.State) error {
+			amount, err := ststo.StateTokenHolderPartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if amount.OverZero() {
+				contract, _ := doc["contract"].(string)
+				partition, _ := doc["partition"].(string)
+				portfolio.STO = append(portfolio.STO, PortfolioSTOBalance{
+					Contract:  contract,
+					Partition: partition,
+					Balance:   amount,
+				})
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := latestStatesOf(st, defaultColNameDIDCredential, bson.D{{"d.value.credential.holder", address}},
+		[]string{"contract", "template", "credential_id"},
+		func(doc bson.M, sta mitumbase.State) error {
+			credential, isActive, err := credentialstate.StateCredentialValue(sta)
+			if err != nil {
+				return err
+			}
+
+			// NOTE the credential may be handed over to another holder.
+			if credential.Holder().String() != address {
+				return nil
+			}
+
+			contract, _ := doc["contract"].(string)
+			template, _ := doc["template"].(string)
+			credentialID, _ := doc["credential_id"].(string)
+			portfolio.Credentials = append(portfolio.Credentials, PortfolioCredential{
+				Contract:     contract,
+				Template:     template,
+				CredentialID: credentialID,
+				Status:       CredentialLifecycle(credential, isActive, now),
+			})
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := latestStatesOf(st, defaultColNameDAODelegators, bson.D{{"delegator_accounts", address}},
+		[]string{"contract", "proposal_id"},
+		func(doc bson.M, sta mitumbase.State) error {
+			delegators, err := daostate.StateDelegatorsValue(sta)
+			if err != nil {
+				return err
+			}
+
+			for i := range delegators {
+				if delegators[i].Account().String() != address {
+					continue
+				}
+
+				contract, _ := doc["contract"].(string)
+				proposalID, _ := doc["proposal_id"].(string)
+				portfolio.Delegations = append(portfolio.Delegations, PortfolioDelegation{
+					Contract:   contract,
+					ProposalID: proposalID,
+					Delegatee:  delegators[i].Delegatee().String(),
+				})
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameNFT,
+		bson.D{{"owner", address}, {"istoken", true}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Contract string `bson:"contract"`
+				NFTID    uint64 `bson:"nftid"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			if int64(len(portfolio.NFTs)) >= maxPortfolioNFTs {
+				portfolio.NFTsTruncated = true
+
+				return false, nil
+			}
+
+			portfolio.NFTs = append(portfolio.NFTs, PortfolioNFT{Contract: doc.Contract, NFTID: doc.NFTID})
+
+			return true, nil
+		},
+		options.Find().SetSort(bson.D{{"contract", 1}, {"nftid", 1}}).SetLimit(maxPortfolioNFTs+1),
+	); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(portfolio.Currencies, func(i, j int) bool {
+		return portfolio.Currencies[i].Currency().String() < portfolio.Currencies[j].Currency().String()
+	})
+	sortPortfolioBalances(portfolio.Tokens)
+	sortPortfolioBalances(portfolio.Points)
+	sort.Slice(portfolio.STO, func(i, j int) bool {
+		if portfolio.STO[i].Contract != portfolio.STO[j].Contract {
+			return portfolio.STO[i].Contract < portfolio.STO[j].Contract
+		}
+
+		return portfolio.STO[i].Partition < portfolio.STO[j].Partition
+	})
+	sort.Slice(portfolio.Credentials, func(i, j int) bool {
+		a, b := portfolio.Credentials[i], portfolio.Credentials[j]
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		if a.Template != b.Template {
+			return a.Template < b.Template
+		}
+
+		return a.CredentialID < b.CredentialID
+	})
+	sort.Slice(portfolio.Delegations, func(i, j int) bool {
+		a, b := portfolio.Delegations[i], portfolio.Delegations[j]
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+
+		return lessProposalID(a.ProposalID, b.ProposalID)
+	})
+
+	return &portfolio, nil
+}
+
+func sortPortfolioBalances(balances []PortfolioBalance) {
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Contract < balances[j].Contract
+	})
+}
@@ -0,0 +1,329 @@
+package digest
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SearchTypeBlock      = "block"
+	SearchTypeOperation  = "operation"
+	SearchTypeAccount    = "account"
+	SearchTypeContract   = "contract"
+	SearchTypeToken      = "token"
+	SearchTypePoint      = "point"
+	SearchTypeNFT        = "nft"
+	SearchTypeCredential = "credential"
+)
+
+var maxSearchResults int64 = 20
+
+var (
+	reSearchHeight  = regexp.MustCompile(`^[0-9]+$`)
+	reSearchAddress = regexp.MustCompile(`^(?i)` + mitumbase.REStringAddressString + `$`)
+	reSearchHash    = regexp.MustCompile(`^[0-9a-zA-Z]{32,}$`)
+	reSearchSymbol  = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_.!$*@]{1,8}[A-Z0-9]$`)
+)
+
+type SearchResult struct {
+	Type     string              `json:"type"`
+	ID       string              `json:"id"`
+	Contract string              `json:"contract,omitempty"`
+	Template string              `json:"template,omitempty"`
+	Height   mitumbase.Height    `json:"height,omitempty"`
+	Models   []ContractModelInfo `json:"models,omitempty"`
+}
+
+const (
+	searchLookupBlockHeight = "block_height"
+	searchLookupBlockHash   = "block_hash"
+	searchLookupOperation   = "operation"
+	searchLookupAccount     = "account"
+	searchLookupContract    = "contract"
+	searchLookupNFT         = "nft"
+	searchLookupToken       = "token"
+	searchLookupPoint       = "point"
+	searchLookupCredential  = "credential"
+)
+
+var searchLookupFuncs = map[string]func(*currencydigest.Database, string) ([]SearchResult, error){
+	searchLookupBlockHeight: func(st *currencydigest.Database, q string) ([]SearchResult, error) {
+		n, err := strconv.ParseUint(q, 10, 63)
+		if err != nil {
+			return nil, nil
+		}
+
+		return searchBlockByHeight(st, mitumbase.Height(n))
+	},
+	searchLookupNFT: func(st *currencydigest.Database, q string) ([]SearchResult, error) {
+		n, err := strconv.ParseUint(q, 10, 63)
+		if err != nil {
+			return nil, nil
+		}
+
+		return searchNFTs(st, n)
+	},
+	searchLookupBlockHash: searchBlockByHash,
+	searchLookupOperation: searchOperation,
+	searchLookupAccount:   searchAccount,
+	searchLookupContract:  searchContract,
+	searchLookupToken: func(st *currencydigest.Database, q string) ([]SearchResult, error) {
+		return searchSymbol(st, SearchTypeToken, defaultColNameToken, strings.ToUpper(q))
+	},
+	searchLookupPoint: func(st *currencydigest.Database, q string) ([]SearchResult, error) {
+		return searchSymbol(st, SearchTypePoint, defaultColNamePoint, strings.ToUpper(q))
+	},
+	searchLookupCredential: searchCredentials,
+}
+
+// searchLookups returns the lookups of every kind q can be. The kinds are not
+// exclusive; a number is both a block height and a nft id, and a hash also
+// looks like an address.
+func searchLookups(q string) []string {
+	var lookups []string
+
+	if reSearchHeight.MatchString(q) {
+		lookups = append(lookups, searchLookupBlockHeight, searchLookupNFT)
+	}
+
+	if reSearchAddress.MatchString(q) {
+		lookups = append(lookups, searchLookupAccount, searchLookupContract)
+	}
+
+	if reSearchHash.MatchString(q) {
+		lookups = append(lookups, searchLookupOperation, searchLookupBlockHash)
+	}
+
+	if reSearchSymbol.MatchString(strings.ToUpper(q)) {
+		lookups = append(lookups, searchLookupToken, searchLookupPoint)
+	}
+
+	// NOTE the credential id is given by the issuer, so any q can be one.
+	return append(lookups, searchLookupCredential)
+}
+
+// Search looks q up in the collections of every kind q can be.
+func Search(st *currencydigest.Database, q string) ([]SearchResult, error) {
+	var results []SearchResult
+
+	lookups := searchLookups(q)
+	for i := range lookups {
+		r, err := searchLookupFuncs[lookups[i]](st, q)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, r...)
+	}
+
+	return results, nil
+}
+
+func searchBlockByHeight(st *currencydigest.Database, height mitumbase.Height) ([]SearchResult, error) {
+	if height > st.LastBlock() {
+		return nil, nil
+	}
+
+	m, _, _, _, _, err := st.ManifestByHeight(height)
+	if err != nil || m == nil {
+		return nil, nil
+	}
+
+	return []SearchResult{{Type: SearchTypeBlock, ID: m.Hash().String(), Height: height}}, nil
+}
+
+func searchBlockByHash(st *currencydigest.Database, hash string) ([]SearchResult, error) {
+	var results []SearchResult
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameBlock,
+		bson.D{{"block", hash}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Height mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			results = append(results, SearchResult{Type: SearchTypeBlock, ID: hash, Height: doc.Height})
+
+			return false, nil
+		},
+		options.Find().SetLimit(1),
+	); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func searchOperation(st *currencydigest.Database, fact string) ([]SearchResult, error) {
+	var results []SearchResult
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameOperation,
+		bson.D{{"fact", fact}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Height mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			results = append(results, SearchResult{Type: SearchTypeOperation, ID: fact, Height: doc.Height})
+
+			return false, nil
+		},
+		options.Find().SetLimit(1),
+	); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func searchAccount(st *currencydigest.Database, address string) ([]SearchResult, error) {
+	count, err := st.DatabaseClient().Count(
+		context.Background(),
+		defaultColNameAccount,
+		bson.D{{"address", address}},
+		options.Count().SetLimit(1),
+	)
+	if err != nil || count < 1 {
+		return nil, err
+	}
+
+	return []SearchResult{{Type: SearchTypeAccount, ID: address}}, nil
+}
+
+func searchContract(st *currencydigest.Database, address string) ([]SearchResult, error) {
+	contracts, err := contractAccounts(st, bson.D{{"address", address}}, nil)
+	if err != nil || len(contracts) < 1 {
+		return nil, err
+	}
+
+	if err := fillContractModels(st, contracts, nil); err != nil {
+		return nil, err
+	}
+
+	return []SearchResult{{
+		Type:   SearchTypeContract,
+		ID:     address,
+		Height: contracts[0].Height,
+		Models: contracts[0].Models,
+	}}, nil
+}
+
+func searchSymbol(st *currencydigest.Database, kind, col, symbol string) ([]SearchResult, error) {
+	contracts, err := st.DatabaseClient().Collection(col).Distinct(
+		context.Background(),
+		"contract",
+		bson.D{{"d.value.design.symbol", symbol}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for i := range contracts {
+		if contract, ok := contracts[i].(string); ok {
+			results = append(results, SearchResult{Type: kind, ID: symbol, Contract: contract})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Contract < results[j].Contract
+	})
+
+	if int64(len(results)) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+
+	return results, nil
+}
+
+func searchNFTs(st *currencydigest.Database, id uint64) ([]SearchResult, error) {
+	var results []SearchResult
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameNFT,
+		bson.D{{"nftid", id}, {"istoken", true}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Contract string           `bson:"contract"`
+				Height   mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			results = append(results, SearchResult{
+				Type:     SearchTypeNFT,
+				ID:       strconv.FormatUint(id, 10),
+				Contract: doc.Contract,
+				Height:   doc.Height,
+			})
+
+			return true, nil
+		},
+		options.Find().SetSort(bson.D{{"contract", 1}}).SetLimit(maxSearchResults),
+	); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func searchCredentials(st *currencydigest.Database, credentialID string) ([]SearchResult, error) {
+	cursor, err := st.DatabaseClient().Collection(defaultColNameDIDCredential).Aggregate(
+		context.Background(),
+		mongo.Pipeline{
+			{{"$match", bson.D{{"credential_id", credentialID}}}},
+			{{"$group", bson.D{
+				{"_id", bson.D{{"contract", "$contract"}, {"template", "$template"}}},
+				{"height", bson.D{{"$max", "$height"}}},
+			}}},
+			{{"$sort", bson.D{{"_id.contract", 1}, {"_id.template", 1}}}},
+			{{"$limit", maxSearchResults}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID struct {
+			Contract string `bson:"contract"`
+			Template string `bson:"template"`
+		} `bson:"_id"`
+		Height mitumbase.Height `bson:"height"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, len(docs))
+	for i := range docs {
+		results[i] = SearchResult{
+			Type:     SearchTypeCredential,
+			ID:       credentialID,
+			Contract: docs[i].ID.Contract,
+			Template: docs[i].ID.Template,
+			Height:   docs[i].Height,
+		}
+	}
+
+	return results, nil
+}
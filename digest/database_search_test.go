@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"testing"
+)
+
+func TestSearchLookups(t *testing.T) {
+	cases := []struct {
+		name     string
+		q        string
+		expected []string
+		not      []string
+	}{
+		{
+			name:     "base58 fact hash",
+			q:        "5VzNKH6xhsQ2ZuP2fYvoS5ejRzEHvqxtz8khfUPYFrqj",
+			expected: []string{searchLookupOperation, searchLookupBlockHash, searchLookupCredential},
+			not:      []string{searchLookupBlockHeight},
+		},
+		{
+			name:     "address",
+			q:        "0x1b2D23a2CaA3D8fC9e4C7F2c7Ff05Fd1D1C1F5D1mca",
+			expected: []string{searchLookupAccount, searchLookupContract, searchLookupCredential},
+			not:      []string{searchLookupBlockHeight},
+		},
+		{
+			name:     "height",
+			q:        "123",
+			expected: []string{searchLookupBlockHeight, searchLookupNFT, searchLookupCredential},
+			not:      []string{searchLookupOperation},
+		},
+		{
+			name:     "symbol",
+			q:        "mcc",
+			expected: []string{searchLookupToken, searchLookupPoint, searchLookupCredential},
+			not:      []string{searchLookupOperation, searchLookupBlockHeight},
+		},
+		{
+			name:     "credential id",
+			q:        "credential-1",
+			expected: []string{searchLookupCredential},
+			not:      []string{searchLookupOperation, searchLookupBlockHeight},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lookups := map[string]bool{}
+			for _, l := range searchLookups(c.q) {
+				if _, found := searchLookupFuncs[l]; !found {
+					t.Errorf("unknown lookup, %q", l)
+				}
+
+				lookups[l] = true
+			}
+
+			for _, l := range c.expected {
+				if !lookups[l] {
+					t.Errorf("expected lookup %q, but %v", l, lookups)
+				}
+			}
+
+			for _, l := range c.not {
+				if lookups[l] {
+					t.Errorf("unexpected lookup %q", l)
+				}
+			}
+		})
+	}
+}
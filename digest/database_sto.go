@@ -1,6 +1,10 @@
 package digest
 
 import (
+	"fmt"
+	"math/big"
+	"sort"
+
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	crcydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
@@ -8,6 +12,8 @@ import (
 	typesto "github.com/ProtoconNet/mitum-sto/types/sto"
 	"github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -195,6 +201,40 @@ func STOPartitionBalance(
 	return amount, nil
 }
 
+func STOPartitionControllers(
+	st *crcydigest.Database,
+	contract,
+	partition string,
+) ([]base.Address, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("partition", partition)
+
+	var controllers []base.Address
+	var sta base.State
+	var err error
+	if err = st.DatabaseClient().GetByFilter(
+		defaultColNameSTOPartitionControllers,
+		filter.D(),
+		func(res *mongo.SingleResult) error {
+			sta, err = crcydigest.LoadState(res.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return err
+			}
+			controllers, err = ststo.StatePartitionControllersValue(sta)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+	); err != nil {
+		return nil, err
+	}
+
+	return controllers, nil
+}
+
 func STOOperatorHolders(
 	st *crcydigest.Database,
 	contract,
@@ -228,3 +268,474 @@ func STOOperatorHolders(
 
 	return holders, nil
 }
+
+// stoLatestStates calls callback with the latest state at or below the height
+// of each group of documents of the contract in the collection col.
+func stoLatestStates(
+	st *crcydigest.Database,
+	col, contract string,
+	height *base.Height,
+	filter bson.D,
+	keys []string,
+	stages mongo.Pipeline,
+	callback func(doc bson.M, sta base.State) error,
+) error {
+	match := bson.D{{"contract", contract}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+	match = append(match, filter...)
+
+	return latestStates(st, col, match, keys, stages, callback)
+}
+
+type STOCapTablePartition struct {
+	Partition  string     `json:"partition"`
+	Balance    common.Big `json:"balance"`
+	Percentage string     `json:"percentage"`
+	Operators  []string   `json:"operators"`
+}
+
+type STOCapTableHolder struct {
+	Holder     string                 `json:"holder"`
+	Balance    common.Big             `json:"balance"`
+	Percentage string                 `json:"percentage"`
+	Partitions []STOCapTablePartition `json:"partitions"`
+}
+
+type STOCapTable struct {
+	Height      base.Height         `json:"height"`
+	TotalSupply common.Big          `json:"total_supply"`
+	Partitions  map[string]string   `json:"partitions"`
+	Holders     []STOCapTableHolder `json:"holders"`
+}
+
+// STOCapTableAt returns the balances of every holder of the contract per
+// partition at the height. The total supply is the sum of the partition
+// balances, and the percentages are against the total supply. Holders with no
+// balance left are excluded.
+func STOCapTableAt(st *crcydigest.Database, contract string, height base.Height) (*STOCapTable, error) {
+	table := STOCapTable{
+		Height:      height,
+		TotalSupply: common.ZeroBig,
+		Partitions:  map[string]string{},
+	}
+
+	if err := stoLatestStates(st, defaultColNameSTOPartitionBalance, contract, &height, nil, []string{"partition"}, nil,
+		func(doc bson.M, sta base.State) error {
+			amount, err := ststo.StatePartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			partition, _ := doc["partition"].(string)
+			table.Partitions[partition] = amount.String()
+			table.TotalSupply = table.TotalSupply.Add(amount)
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	operators := map[string][]string{}
+	if err := stoLatestStates(
+		st, defaultColNameSTOHolderPartitionOperators, contract, &height, nil, []string{"holder", "partition"}, nil,
+		func(doc bson.M, sta base.State) error {
+			oprs, err := ststo.StateTokenHolderPartitionOperatorsValue(sta)
+			if err != nil {
+				return err
+			}
+
+			holder, _ := doc["holder"].(string)
+			partition, _ := doc["partition"].(string)
+
+			as := make([]string, len(oprs))
+			for i := range oprs {
+				as[i] = oprs[i].String()
+			}
+			operators[holder+":"+partition] = as
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	holders := map[string]*STOCapTableHolder{}
+	if err := stoLatestStates(
+		st, defaultColNameSTOHolderPartitionBalance, contract, &height, nil, []string{"holder", "partition"}, nil,
+		func(doc bson.M, sta base.State) error {
+			amount, err := ststo.StateTokenHolderPartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if !amount.OverZero() {
+				return nil
+			}
+
+			holder, _ := doc["holder"].(string)
+			partition, _ := doc["partition"].(string)
+
+			h, found := holders[holder]
+			if !found {
+				h = &STOCapTableHolder{Holder: holder, Balance: common.ZeroBig}
+				holders[holder] = h
+			}
+
+			ops := operators[holder+":"+partition]
+			if ops == nil {
+				ops = []string{}
+			}
+
+			h.Balance = h.Balance.Add(amount)
+			h.Partitions = append(h.Partitions, STOCapTablePartition{
+				Partition:  partition,
+				Balance:    amount,
+				Percentage: stoPercentage(amount, table.TotalSupply),
+				Operators:  ops,
+			})
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	table.Holders = make([]STOCapTableHolder, 0, len(holders))
+	for _, h := range holders {
+		h.Percentage = stoPercentage(h.Balance, table.TotalSupply)
+		sort.Slice(h.Partitions, func(i, j int) bool {
+			return h.Partitions[i].Partition < h.Partitions[j].Partition
+		})
+
+		table.Holders = append(table.Holders, *h)
+	}
+
+	sort.Slice(table.Holders, func(i, j int) bool {
+		switch c := table.Holders[i].Balance.Compare(table.Holders[j].Balance); {
+		case c != 0:
+			return c > 0
+		default:
+			return table.Holders[i].Holder < table.Holders[j].Holder
+		}
+	})
+
+	return &table, nil
+}
+
+// stoPercentage returns the percentage of amount in total with 4 decimal
+// places.
+func stoPercentage(amount, total common.Big) string {
+	if !total.OverZero() {
+		return "0.0000"
+	}
+
+	return new(big.Rat).SetFrac(
+		new(big.Int).Mul(amount.Int, big.NewInt(100)),
+		total.Int,
+	).FloatString(4)
+}
+
+type STOPartition struct {
+	Partition string     `json:"partition"`
+	Balance   common.Big `json:"balance"`
+	Holders   int64      `json:"holders"`
+}
+
+// STOPartitions returns the partitions of the contract with their balances
+// and the number of holders which have balance in each partition. With
+// height, the balances after the height are ignored.
+func STOPartitions(st *crcydigest.Database, contract string, height *base.Height) ([]STOPartition, error) {
+	partitions := map[string]*STOPartition{}
+
+	partition := func(name string) *STOPartition {
+		if i, found := partitions[name]; found {
+			return i
+		}
+
+		i := &STOPartition{Partition: name, Balance: common.ZeroBig}
+		partitions[name] = i
+
+		return i
+	}
+
+	if err := stoLatestStates(st, defaultColNameSTOPartitionBalance, contract, height, nil, []string{"partition"}, nil,
+		func(doc bson.M, sta base.State) error {
+			amount, err := ststo.StatePartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			name, _ := doc["partition"].(string)
+			partition(name).Balance = amount
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := stoLatestStates(
+		st, defaultColNameSTOHolderPartitionBalance, contract, height, nil, []string{"holder", "partition"}, nil,
+		func(doc bson.M, sta base.State) error {
+			amount, err := ststo.StateTokenHolderPartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			if amount.OverZero() {
+				name, _ := doc["partition"].(string)
+				partition(name).Holders++
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	pts := make([]STOPartition, 0, len(partitions))
+	for _, i := range partitions {
+		pts = append(pts, *i)
+	}
+
+	sort.Slice(pts, func(i, j int) bool {
+		return pts[i].Partition < pts[j].Partition
+	})
+
+	return pts, nil
+}
+
+type STOPartitionHolder struct {
+	Holder  string      `json:"holder"`
+	Balance common.Big  `json:"balance"`
+	Height  base.Height `json:"height"`
+}
+
+// STOPartitionHolders calls callback with the latest balance of each holder
+// of the partition ordered by holder address. holder is nil for the holders
+// without balance; they still move the offset of the next page, which is
+// given by last. With height, the balances after the height are ignored.
+func STOPartitionHolders(
+	st *crcydigest.Database,
+	contract, partition, offset string,
+	reverse bool,
+	limit int64,
+	height *base.Height,
+	callback func(holder *STOPartitionHolder, last string) error,
+) error {
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	var stages mongo.Pipeline
+	if len(offset) > 0 {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+
+		stages = append(stages, bson.D{{"$match", bson.D{{"_id.holder", bson.D{{op, offset}}}}}})
+	}
+
+	stages = append(stages, bson.D{{"$sort", bson.D{{"_id.holder", sr}}}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		stages = append(stages, bson.D{{"$limit", maxLimit}})
+	default:
+		stages = append(stages, bson.D{{"$limit", limit}})
+	}
+
+	return stoLatestStates(
+		st, defaultColNameSTOHolderPartitionBalance, contract, height,
+		bson.D{{"partition", partition}}, []string{"holder"}, stages,
+		func(doc bson.M, sta base.State) error {
+			amount, err := ststo.StateTokenHolderPartitionBalanceValue(sta)
+			if err != nil {
+				return err
+			}
+
+			holder, _ := doc["holder"].(string)
+
+			if !amount.OverZero() {
+				return callback(nil, holder)
+			}
+
+			return callback(&STOPartitionHolder{Holder: holder, Balance: amount, Height: sta.Height()}, holder)
+		},
+	)
+}
+
+var (
+	STOTransferReasonInvalidRequest      = "invalid_request"
+	STOTransferReasonUnknownPartition    = "unknown_partition"
+	STOTransferReasonInsufficientBalance = "insufficient_partition_balance"
+	STOTransferReasonOperatorNotAllowed  = "operator_not_authorized"
+)
+
+type STOTransferCheck struct {
+	Sender    string
+	Receiver  string
+	Partition string
+	Amount    common.Big
+	Operator  string
+}
+
+type STOTransferCheckResult struct {
+	CanTransfer bool        `json:"can_transfer"`
+	Reason      string      `json:"reason,omitempty"`
+	Message     string      `json:"message,omitempty"`
+	Balance     *common.Big `json:"balance,omitempty"`
+	Height      base.Height `json:"height"`
+}
+
+// STOCanTransfer checks the transfer of the partition against the digested
+// state at the last block, in the order of the transfer processor of
+// mitum-sto; the first failed check is returned as the reason. An operator
+// other than the sender must be an operator of the sender for the partition.
+func STOCanTransfer(st *crcydigest.Database, contract string, check STOTransferCheck) (STOTransferCheckResult, error) {
+	height := st.LastBlock()
+	result := STOTransferCheckResult{Height: height}
+
+	fail := func(reason, format string, args ...interface{}) (STOTransferCheckResult, error) {
+		result.Reason = reason
+		result.Message = fmt.Sprintf(format, args...)
+
+		return result, nil
+	}
+
+	switch {
+	case check.Sender == check.Receiver:
+		return fail(STOTransferReasonInvalidRequest, "sender and receiver are same, %s", check.Sender)
+	case !check.Amount.OverZero():
+		return fail(STOTransferReasonInvalidRequest, "amount must be over zero, %s", check.Amount)
+	}
+
+	switch _, err := stoStateAt(st, defaultColNameSTO, bson.D{{"contract", contract}}, height); {
+	case errors.Is(err, mitumutil.ErrNotFound):
+		return result, mitumutil.ErrNotFound.WithMessage(err, "sto service, contract %s", contract)
+	case err != nil:
+		return result, err
+	}
+
+	switch _, err := stoStateAt(st, defaultColNameSTOPartitionBalance,
+		bson.D{{"contract", contract}, {"partition", check.Partition}}, height); {
+	case errors.Is(err, mitumutil.ErrNotFound):
+		return fail(STOTransferReasonUnknownPartition, "unknown partition, %s", check.Partition)
+	case err != nil:
+		return result, err
+	}
+
+	var partitions []typesto.Partition
+	switch sta, err := stoStateAt(st, defaultColNameSTOHolderPartitions,
+		bson.D{{"contract", contract}, {"holder", check.Sender}}, height); {
+	case errors.Is(err, mitumutil.ErrNotFound):
+	case err != nil:
+		return result, err
+	default:
+		if partitions, err = ststo.StateTokenHolderPartitionsValue(sta); err != nil {
+			return result, err
+		}
+	}
+
+	var found bool
+	for i := range partitions {
+		if partitions[i].String() == check.Partition {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		return fail(STOTransferReasonUnknownPartition,
+			"partition %s not found in sender partitions, %s", check.Partition, check.Sender)
+	}
+
+	if len(check.Operator) > 0 && check.Operator != check.Sender {
+		var operators []base.Address
+		switch sta, err := stoStateAt(st, defaultColNameSTOHolderPartitionOperators,
+			bson.D{{"contract", contract}, {"holder", check.Sender}, {"partition", check.Partition}}, height); {
+		case errors.Is(err, mitumutil.ErrNotFound):
+		case err != nil:
+			return result, err
+		default:
+			if operators, err = ststo.StateTokenHolderPartitionOperatorsValue(sta); err != nil {
+				return result, err
+			}
+		}
+
+		var allowed bool
+		for i := range operators {
+			if operators[i].String() == check.Operator {
+				allowed = true
+
+				break
+			}
+		}
+
+		if !allowed {
+			return fail(STOTransferReasonOperatorNotAllowed,
+				"operator %s is not an operator of sender %s for partition %s",
+				check.Operator, check.Sender, check.Partition)
+		}
+	}
+
+	balance := common.ZeroBig
+	switch sta, err := stoStateAt(st, defaultColNameSTOHolderPartitionBalance,
+		bson.D{{"contract", contract}, {"holder", check.Sender}, {"partition", check.Partition}}, height); {
+	case errors.Is(err, mitumutil.ErrNotFound):
+	case err != nil:
+		return result, err
+	default:
+		if balance, err = ststo.StateTokenHolderPartitionBalanceValue(sta); err != nil {
+			return result, err
+		}
+	}
+	result.Balance = &balance
+
+	if balance.Compare(check.Amount) < 0 {
+		return fail(STOTransferReasonInsufficientBalance,
+			"insufficient partition balance of sender %s, %s < %s", check.Sender, balance, check.Amount)
+	}
+
+	result.CanTransfer = true
+
+	return result, nil
+}
+
+// stoStateAt returns the latest state at or below the height of the documents
+// matched by filter in the collection col. It returns mitumutil.ErrNotFound
+// when none is matched.
+func stoStateAt(st *crcydigest.Database, col string, filter bson.D, height base.Height) (base.State, error) {
+	filter = append(filter, bson.E{Key: "height", Value: bson.D{{"$lte", height}}})
+
+	var sta base.State
+	if err := st.DatabaseClient().GetByFilter(
+		col,
+		filter,
+		func(res *mongo.SingleResult) error {
+			i, err := crcydigest.LoadState(res.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return err
+			}
+			sta = i
+
+			return nil
+		},
+		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+	); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mitumutil.ErrNotFound.WithMessage(err, "state in %s", col)
+		}
+
+		return nil, err
+	}
+
+	return sta, nil
+}
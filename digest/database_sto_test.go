@@ -0,0 +1,33 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+)
+
+func TestSTOPercentage(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   int64
+		total    int64
+		expected string
+	}{
+		{name: "zero total", amount: 10, total: 0, expected: "0.0000"},
+		{name: "zero amount", amount: 0, total: 10, expected: "0.0000"},
+		{name: "all", amount: 10, total: 10, expected: "100.0000"},
+		{name: "half", amount: 1, total: 2, expected: "50.0000"},
+		{name: "third", amount: 1, total: 3, expected: "33.3333"},
+		{name: "rounded", amount: 2, total: 3, expected: "66.6667"},
+		{name: "tiny", amount: 1, total: 10000000, expected: "0.0000"},
+		{name: "smallest", amount: 1, total: 1000000, expected: "0.0001"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if p := stoPercentage(common.NewBig(c.amount), common.NewBig(c.total)); p != c.expected {
+				t.Errorf("expected %s, but %s", c.expected, p)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package digest
 
 import (
+	"context"
+	"time"
+
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
 	timestampservice "github.com/ProtoconNet/mitum-timestamp/state"
@@ -8,6 +11,7 @@ import (
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -85,3 +89,235 @@ func TimestampItem(st *currencydigest.Database, contract, project string, idx ui
 		return types.TimeStampItem{}, nil, errors.Errorf("state is nil")
 	}
 }
+
+type TimestampProject struct {
+	Project              string `json:"project" bson:"_id"`
+	Items                int64  `json:"items" bson:"items"`
+	LastRequestTimestamp int64  `json:"last_request_timestamp" bson:"last_request_timestamp"`
+	LastTimestampIdx     uint64 `json:"last_timestampidx" bson:"last_timestampidx"`
+	LastHeight           int64  `json:"last_height" bson:"last_height"`
+}
+
+// TimestampProjects summarizes the projects of the timestamp service with the
+// number of items and the latest item of each, ordered by project. The items
+// after height are not counted.
+func TimestampProjects(
+	st *currencydigest.Database,
+	contract, offset string,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+) ([]TimestampProject, error) {
+	pipeline := timestampProjectsPipeline(contract, height)
+
+	if len(offset) > 0 {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+		pipeline = append(pipeline, bson.D{{"$match", bson.D{{"_id", bson.D{{op, offset}}}}}})
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+	pipeline = append(pipeline, bson.D{{"$sort", bson.D{{"_id", sr}}}})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		pipeline = append(pipeline, bson.D{{"$limit", maxLimit}})
+	default:
+		pipeline = append(pipeline, bson.D{{"$limit", limit}})
+	}
+
+	cursor, err := st.DatabaseClient().Collection(defaultColNameTimeStamp).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []TimestampProject
+	if err := cursor.All(context.Background(), &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// TimestampProjectCount counts the projects of the timestamp service.
+func TimestampProjectCount(st *currencydigest.Database, contract string, height *mitumbase.Height) (int64, error) {
+	pipeline := append(timestampProjectsPipeline(contract, height), bson.D{{"$count", "n"}})
+
+	cursor, err := st.DatabaseClient().Collection(defaultColNameTimeStamp).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var docs []struct {
+		N int64 `bson:"n"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil || len(docs) < 1 {
+		return 0, err
+	}
+
+	return docs[0].N, nil
+}
+
+func timestampProjectsPipeline(contract string, height *mitumbase.Height) mongo.Pipeline {
+	match := bson.D{{"contract", contract}, {"isItem", true}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	return mongo.Pipeline{
+		{{"$match", match}},
+		{{"$group", bson.D{
+			{"_id", "$project"},
+			{"items", bson.D{{"$sum", 1}}},
+			{"last_request_timestamp", bson.D{{"$max", "$request_timestamp"}}},
+			{"last_timestampidx", bson.D{{"$max", "$timestampidx"}}},
+			{"last_height", bson.D{{"$max", "$height"}}},
+		}}},
+	}
+}
+
+func TimestampItemsByProject(
+	st *currencydigest.Database,
+	contract, project string,
+	offset *uint64,
+	reverse bool,
+	limit int64,
+	from, to *uint64,
+	height *mitumbase.Height,
+	callback func(types.TimeStampItem, mitumbase.State) (bool, error),
+) error {
+	filterA := bson.A{
+		bson.D{{"contract", contract}},
+		bson.D{{"project", project}},
+		bson.D{{"isItem", true}},
+	}
+
+	if height != nil {
+		filterA = append(filterA, bson.D{{"height", bson.D{{"$lte", *height}}}})
+	}
+
+	if offset != nil {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+		filterA = append(filterA, bson.D{{"timestampidx", bson.D{{op, *offset}}}})
+	}
+
+	// NOTE request_timestamp of the older items is filled by
+	// backfillTimeStampRequestTimestamp at start.
+	if from != nil {
+		filterA = append(filterA, bson.D{{"request_timestamp", bson.D{{"$gte", int64(*from)}}}})
+	}
+
+	if to != nil {
+		filterA = append(filterA, bson.D{{"request_timestamp", bson.D{{"$lte", int64(*to)}}}})
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(
+		util.NewBSONFilter("timestampidx", sr).D(),
+	)
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameTimeStamp,
+		bson.D{{"$and", filterA}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			it, err := timestampservice.StateTimeStampItemValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(it, sta)
+		},
+		opt,
+	)
+}
+
+type TimestampMatch struct {
+	Project      string           `json:"project"`
+	TimestampIdx uint64           `json:"timestampidx"`
+	Height       mitumbase.Height `json:"height"`
+	ConfirmedAt  time.Time        `json:"confirmed_at"`
+}
+
+// TimestampItemsByDataHash finds the items of the timestamp service whose
+// data is the given hash, oldest first. The items after height are excluded.
+func TimestampItemsByDataHash(
+	st *currencydigest.Database,
+	contract, hash string,
+	height *mitumbase.Height,
+) ([]TimestampMatch, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("isItem", true)
+	filter = filter.Add("data_hash", hash)
+	if height != nil {
+		filter = filter.Add("height", bson.D{{"$lte", *height}})
+	}
+
+	var matches []TimestampMatch
+	times := map[mitumbase.Height]time.Time{}
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameTimeStamp,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Project      string           `bson:"project"`
+				TimestampIdx uint64           `bson:"timestampidx"`
+				Height       mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			t, found := times[doc.Height]
+			if !found {
+				i, err := BlockTime(st, doc.Height)
+				if err != nil {
+					return false, err
+				}
+				t = i
+				times[doc.Height] = t
+			}
+
+			matches = append(matches, TimestampMatch{
+				Project:      doc.Project,
+				TimestampIdx: doc.TimestampIdx,
+				Height:       doc.Height,
+				ConfirmedAt:  t,
+			})
+
+			return true, nil
+		},
+		options.Find().SetSort(bson.D{{"height", 1}, {"project", 1}, {"timestampidx", 1}}),
+	); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
@@ -1,6 +1,9 @@
 package digest
 
 import (
+	"context"
+	"strings"
+
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
@@ -8,6 +11,8 @@ import (
 	"github.com/ProtoconNet/mitum-token/types"
 	mitumbase "github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -72,3 +77,348 @@ func TokenBalance(st *currencydigest.Database, contract, account string) (common
 
 	return amount, nil
 }
+
+// latestTokenBalance returns the last indexed balance of account; found is
+// false when the account never held the token.
+func latestTokenBalance(st *currencydigest.Database, contract, account string) (common.Big, bool, error) {
+	filter := util.NewBSONFilter("contract", contract)
+	filter = filter.Add("address", account)
+
+	amount := common.ZeroBig
+	var found bool
+	if err := st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameTokenBalance,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := currencydigest.LoadState(cursor.Decode, st.DatabaseEncoders())
+			if err != nil {
+				return false, err
+			}
+
+			i, err := state.StateTokenBalanceValue(sta)
+			if err != nil {
+				return false, err
+			}
+			amount = i
+			found = true
+
+			return false, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", -1).D()).SetLimit(1),
+	); err != nil {
+		return common.NilBig, false, err
+	}
+
+	return amount, found, nil
+}
+
+type TokenHolder struct {
+	Address string           `json:"address"`
+	Balance common.Big       `json:"balance"`
+	Height  mitumbase.Height `json:"height"`
+}
+
+// TokenHolderKey is the offset of the holder in the list of holders.
+func TokenHolderKey(h TokenHolder) string {
+	return h.Balance.String() + ":" + h.Address
+}
+
+func parseTokenHolderKey(s string) (string, string, error) {
+	i := strings.Index(s, ":")
+	if i < 1 {
+		return "", "", errors.Errorf("invalid offset, %q", s)
+	}
+
+	balance, err := common.NewBigFromString(s[:i])
+	if err != nil {
+		return "", "", errors.Errorf("invalid offset, %q", s)
+	}
+
+	return balance.String(), s[i+1:], nil
+}
+
+// TokenHolders returns the accounts holding the token ordered by balance,
+// largest first; accounts with the same balance are ordered by address. The
+// balances are compared by balance_len and balance, the decimal string. offset
+// is the key of TokenHolderKey. With height, the balances after the height
+// are ignored.
+func TokenHolders(
+	st *currencydigest.Database,
+	contract, offset string,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+) ([]TokenHolder, error) {
+	switch {
+	case limit <= 0, limit > maxLimit:
+		limit = maxLimit
+	}
+
+	pipeline := tokenHoldersPipeline(contract, height)
+
+	if len(offset) > 0 {
+		balance, address, err := parseTokenHolderKey(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		l := len(balance)
+
+		op, aop := "$lt", "$gt"
+		if reverse {
+			op, aop = "$gt", "$lt"
+		}
+
+		pipeline = append(pipeline, bson.D{{"$match", bson.D{{"$or", bson.A{
+			bson.D{{"balance_len", bson.D{{op, l}}}},
+			bson.D{{"balance_len", l}, {"balance", bson.D{{op, balance}}}},
+			bson.D{{"balance_len", l}, {"balance", balance}, {"_id", bson.D{{aop, address}}}},
+		}}}}})
+	}
+
+	sr := -1
+	if reverse {
+		sr = 1
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{"$sort", bson.D{{"balance_len", sr}, {"balance", sr}, {"_id", -sr}}}},
+		bson.D{{"$limit", limit}},
+	)
+
+	c, err := st.DatabaseClient().Collection(defaultColNameTokenBalance).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		Address string           `bson:"_id"`
+		Balance string           `bson:"balance"`
+		Height  mitumbase.Height `bson:"height"`
+	}
+	if err := c.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	holders := make([]TokenHolder, len(docs))
+	for i := range docs {
+		balance, err := common.NewBigFromString(docs[i].Balance)
+		if err != nil {
+			return nil, err
+		}
+
+		holders[i] = TokenHolder{
+			Address: docs[i].Address,
+			Balance: balance,
+			Height:  docs[i].Height,
+		}
+	}
+
+	return holders, nil
+}
+
+// TokenHolderCount returns the number of the accounts holding the token.
+func TokenHolderCount(st *currencydigest.Database, contract string, height *mitumbase.Height) (int64, error) {
+	pipeline := append(tokenHoldersPipeline(contract, height), bson.D{{"$count", "n"}})
+
+	c, err := st.DatabaseClient().Collection(defaultColNameTokenBalance).Aggregate(
+		context.Background(), pipeline, options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var docs []struct {
+		N int64 `bson:"n"`
+	}
+	if err := c.All(context.Background(), &docs); err != nil || len(docs) < 1 {
+		return 0, err
+	}
+
+	return docs[0].N, nil
+}
+
+func tokenHoldersPipeline(contract string, height *mitumbase.Height) mongo.Pipeline {
+	match := bson.D{{"contract", contract}}
+	if height != nil {
+		match = append(match, bson.E{Key: "height", Value: bson.D{{"$lte", *height}}})
+	}
+
+	return mongo.Pipeline{
+		{{"$match", match}},
+		{{"$sort", bson.D{{"address", 1}, {"height", -1}}}},
+		{{"$group", bson.D{
+			{"_id", "$address"},
+			{"balance", bson.D{{"$first", "$balance"}}},
+			{"balance_len", bson.D{{"$first", "$balance_len"}}},
+			{"height", bson.D{{"$first", "$height"}}},
+		}}},
+		{{"$match", bson.D{{"balance", bson.D{{"$ne", "0"}}}}}},
+	}
+}
+
+// TokenBalanceSummary returns the sum of the latest balances of the token and
+// the number of the accounts which have balance.
+func TokenBalanceSummary(st *currencydigest.Database, contract string) (common.Big, int64, error) {
+	ctx := context.Background()
+
+	c, err := st.DatabaseClient().Collection(defaultColNameTokenBalance).Aggregate(
+		ctx,
+		mongo.Pipeline{
+			{{"$match", bson.D{{"contract", contract}}}},
+			{{"$sort", bson.D{{"address", 1}, {"height", -1}}}},
+			{{"$group", bson.D{{"_id", "$address"}, {"doc", bson.D{{"$first", "$$ROOT"}}}}}},
+			{{"$replaceRoot", bson.D{{"newRoot", "$doc"}}}},
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return common.NilBig, 0, err
+	}
+	defer func() {
+		_ = c.Close(ctx)
+	}()
+
+	total := common.ZeroBig
+	var holders int64
+
+	for c.Next(ctx) {
+		sta, err := currencydigest.LoadState(c.Decode, st.DatabaseEncoders())
+		if err != nil {
+			return common.NilBig, 0, err
+		}
+
+		amount, err := state.StateTokenBalanceValue(sta)
+		if err != nil {
+			return common.NilBig, 0, err
+		}
+
+		if amount.OverZero() {
+			total = total.Add(amount)
+			holders++
+		}
+	}
+
+	if err := c.Err(); err != nil {
+		return common.NilBig, 0, err
+	}
+
+	return total, holders, nil
+}
+
+type TokenSupply struct {
+	TotalSupply       common.Big       `json:"total_supply"`
+	CirculatingSupply common.Big       `json:"circulating_supply"`
+	Holders           int64            `json:"holders"`
+	Height            mitumbase.Height `json:"height"`
+}
+
+// TokenSupplySeries calls callback with the supply snapshots of the token
+// ordered by height. With height, the snapshots after the height are ignored.
+func TokenSupplySeries(
+	st *currencydigest.Database,
+	contract string,
+	offset *mitumbase.Height,
+	reverse bool,
+	limit int64,
+	height *mitumbase.Height,
+	callback func(TokenSupply) (bool, error),
+) error {
+	filterA := tokenSupplyFilter(contract, height)
+
+	if offset != nil {
+		op := "$gt"
+		if reverse {
+			op = "$lt"
+		}
+		filterA = append(filterA, bson.D{{"height", bson.D{{op, *offset}}}})
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(util.NewBSONFilter("height", sr).D())
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return st.DatabaseClient().Find(
+		context.Background(),
+		defaultColNameTokenSupply,
+		bson.D{{"$and", filterA}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				TotalSupply       string           `bson:"total_supply"`
+				CirculatingSupply string           `bson:"circulating_supply"`
+				Holders           int64            `bson:"holders"`
+				Height            mitumbase.Height `bson:"height"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			total, err := common.NewBigFromString(doc.TotalSupply)
+			if err != nil {
+				return false, err
+			}
+
+			circulating, err := common.NewBigFromString(doc.CirculatingSupply)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(TokenSupply{
+				TotalSupply:       total,
+				CirculatingSupply: circulating,
+				Holders:           doc.Holders,
+				Height:            doc.Height,
+			})
+		},
+		opt,
+	)
+}
+
+// TokenSupplyCount returns the number of the supply snapshots of the token.
+func TokenSupplyCount(st *currencydigest.Database, contract string, height *mitumbase.Height) (int64, error) {
+	return st.DatabaseClient().Count(
+		context.Background(),
+		defaultColNameTokenSupply,
+		bson.D{{"$and", tokenSupplyFilter(contract, height)}},
+		options.Count(),
+	)
+}
+
+func tokenSupplyFilter(contract string, height *mitumbase.Height) bson.A {
+	filterA := bson.A{bson.D{{"contract", contract}}}
+
+	if height != nil {
+		filterA = append(filterA, bson.D{{"height", bson.D{{"$lte", *height}}}})
+	}
+
+	return filterA
+}
+
+// LatestTokenSupply returns the last supply snapshot of the token, nil when
+// none was indexed.
+func LatestTokenSupply(st *currencydigest.Database, contract string) (*TokenSupply, error) {
+	var supply *TokenSupply
+	if err := TokenSupplySeries(st, contract, nil, true, 1, nil, func(i TokenSupply) (bool, error) {
+		supply = &i
+
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return supply, nil
+}
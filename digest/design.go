@@ -0,0 +1,40 @@
+package digest
+
+import (
+	"github.com/pkg/errors"
+)
+
+var minCursorSecretSize = 32
+
+// Design is the section of digest design for the digest API of this module,
+// which the digest design of currency does not have,
+//
+//	digest:
+//	  cursor_secret: 2f6c8e3b9a1d4f7e0c5b8a2d6e9f1c4b
+//	  rate_limit:
+//	    default:
+//	      limit: 600
+//	      interval: 1m
+//
+// cursor_secret is the key to sign the page cursors. The nodes behind the
+// same endpoint should share it; without it every node signs with its own
+// random key, so the cursors become invalid after restart. rate_limit is
+// RateLimitDesign.
+type Design struct {
+	CursorSecret string           `yaml:"cursor_secret" json:"-"`
+	RateLimit    *RateLimitDesign `yaml:"rate_limit" json:"rate_limit,omitempty"`
+}
+
+func (d Design) IsValid() error {
+	if n := len(d.CursorSecret); n > 0 && n < minCursorSecretSize {
+		return errors.Errorf("too short cursor_secret, %d; at least %d", n, minCursorSecretSize)
+	}
+
+	if d.RateLimit != nil {
+		if err := d.RateLimit.IsValid(); err != nil {
+			return errors.WithMessage(err, "rate_limit")
+		}
+	}
+
+	return nil
+}
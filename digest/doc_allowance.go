@@ -0,0 +1,37 @@
+package digest
+
+import (
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+// AllowanceDoc is an amount the owner approved the spender to transfer on its
+// behalf, flattened from the approve list of a token or point design.
+type AllowanceDoc struct {
+	contract string
+	owner    string
+	spender  string
+	amount   common.Big
+	height   base.Height
+}
+
+func NewAllowanceDoc(contract, owner, spender string, amount common.Big, height base.Height) AllowanceDoc {
+	return AllowanceDoc{
+		contract: contract,
+		owner:    owner,
+		spender:  spender,
+		amount:   amount,
+		height:   height,
+	}
+}
+
+func (doc AllowanceDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(map[string]interface{}{
+		"contract": doc.contract,
+		"owner":    doc.owner,
+		"spender":  doc.spender,
+		"amount":   doc.amount.String(),
+		"height":   doc.height,
+	})
+}
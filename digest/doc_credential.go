@@ -1,6 +1,10 @@
 package digest
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+
 	"github.com/ProtoconNet/mitum-credential/state"
 	"github.com/ProtoconNet/mitum-credential/types"
 	mongodbstorage "github.com/ProtoconNet/mitum-currency/v3/digest/mongodb"
@@ -127,6 +131,8 @@ func (doc CredentialDoc) MarshalBSON() ([]byte, error) {
 	m["template"] = parsedKey[2]
 	m["credential_id"] = parsedKey[3]
 	m["is_active"] = doc.isActive
+	m["valid_from"] = int64(doc.credential.ValidFrom())
+	m["valid_until"] = int64(doc.credential.ValidUntil())
 	m["height"] = doc.st.Height()
 
 	return bsonenc.Marshal(m)
@@ -174,3 +180,113 @@ func (doc HolderDIDDoc) MarshalBSON() ([]byte, error) {
 
 	return bsonenc.Marshal(m)
 }
+
+// CredentialStatusDoc keeps the status list index assigned to a credential.
+// The index is assigned when the credential is first seen and never changes.
+type CredentialStatusDoc struct {
+	contract     string
+	template     string
+	credentialID string
+	index        uint64
+	height       base.Height
+}
+
+func NewCredentialStatusDoc(
+	contract, template, credentialID string, index uint64, height base.Height,
+) CredentialStatusDoc {
+	return CredentialStatusDoc{
+		contract:     contract,
+		template:     template,
+		credentialID: credentialID,
+		index:        index,
+		height:       height,
+	}
+}
+
+func (doc CredentialStatusDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(map[string]interface{}{
+		"contract":      doc.contract,
+		"template":      doc.template,
+		"credential_id": doc.credentialID,
+		"index":         doc.index,
+		"height":        doc.height,
+	})
+}
+
+// CredentialStatusListDoc is the revocation bitstring of a template at a
+// height.
+type CredentialStatusListDoc struct {
+	contract    string
+	template    string
+	encodedList string
+	size        uint64
+	revoked     uint64
+	height      base.Height
+}
+
+func NewCredentialStatusListDoc(
+	contract, template string, size uint64, revoked []uint64, height base.Height,
+) (CredentialStatusListDoc, error) {
+	encoded, err := EncodeCredentialStatusList(size, revoked)
+	if err != nil {
+		return CredentialStatusListDoc{}, err
+	}
+
+	return CredentialStatusListDoc{
+		contract:    contract,
+		template:    template,
+		encodedList: encoded,
+		size:        size,
+		revoked:     uint64(len(revoked)),
+		height:      height,
+	}, nil
+}
+
+func (doc CredentialStatusListDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(map[string]interface{}{
+		"contract":     doc.contract,
+		"template":     doc.template,
+		"encoded_list": doc.encodedList,
+		"size":         doc.size,
+		"revoked":      doc.revoked,
+		"height":       doc.height,
+	})
+}
+
+// CredentialStatusListMinSize is the minimum bitstring length, 16KB, which
+// StatusList2021 recommends for group privacy.
+var CredentialStatusListMinSize uint64 = 131072
+
+func CredentialStatusListSize(indexes uint64) uint64 {
+	size := CredentialStatusListMinSize
+	for size < indexes {
+		size += CredentialStatusListMinSize
+	}
+
+	return size
+}
+
+// EncodeCredentialStatusList builds the StatusList2021 bitstring; the bit at
+// each revoked index is set, the first index being the most significant bit
+// of the first byte. The bitstring is gzip compressed and base64url encoded.
+func EncodeCredentialStatusList(size uint64, revoked []uint64) (string, error) {
+	bits := make([]byte, (size+7)/8)
+	for _, i := range revoked {
+		if i >= size {
+			continue
+		}
+
+		bits[i/8] |= 0x80 >> (i % 8)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,88 @@
+package digest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"testing"
+)
+
+func decodeCredentialStatusList(t *testing.T, s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bits, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bits
+}
+
+func TestEncodeCredentialStatusList(t *testing.T) {
+	cases := []struct {
+		name    string
+		size    uint64
+		revoked []uint64
+		set     []uint64
+	}{
+		{name: "empty", size: 16},
+		{name: "first", size: 16, revoked: []uint64{0}, set: []uint64{0}},
+		{name: "byte boundary", size: 16, revoked: []uint64{7, 8}, set: []uint64{7, 8}},
+		{name: "last", size: 16, revoked: []uint64{15}, set: []uint64{15}},
+		{name: "out of size", size: 16, revoked: []uint64{3, 16, 100}, set: []uint64{3}},
+		{name: "not byte aligned", size: 12, revoked: []uint64{11}, set: []uint64{11}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := EncodeCredentialStatusList(c.size, c.revoked)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			bits := decodeCredentialStatusList(t, s)
+			if l := uint64(len(bits)); l != (c.size+7)/8 {
+				t.Fatalf("expected %d bytes, but %d", (c.size+7)/8, l)
+			}
+
+			set := map[uint64]bool{}
+			for _, i := range c.set {
+				set[i] = true
+			}
+
+			for i := uint64(0); i < uint64(len(bits))*8; i++ {
+				isSet := bits[i/8]&(0x80>>(i%8)) != 0
+				if isSet != set[i] {
+					t.Errorf("bit %d: expected %v, but %v", i, set[i], isSet)
+				}
+			}
+		})
+	}
+}
+
+func TestCredentialStatusListSize(t *testing.T) {
+	cases := []struct {
+		indexes  uint64
+		expected uint64
+	}{
+		{indexes: 0, expected: CredentialStatusListMinSize},
+		{indexes: 1, expected: CredentialStatusListMinSize},
+		{indexes: CredentialStatusListMinSize, expected: CredentialStatusListMinSize},
+		{indexes: CredentialStatusListMinSize + 1, expected: CredentialStatusListMinSize * 2},
+	}
+
+	for _, c := range cases {
+		if size := CredentialStatusListSize(c.indexes); size != c.expected {
+			t.Errorf("%d: expected %d, but %d", c.indexes, c.expected, size)
+		}
+	}
+}
@@ -84,6 +84,8 @@ func (doc DAOProposalDoc) MarshalBSON() ([]byte, error) {
 	m["height"] = doc.st.Height()
 	m["proposal"] = doc.pr
 	m["proposal_status"] = doc.ps
+	m["proposer"] = doc.pr.Proposer().String()
+	m["option"] = string(doc.pr.Option())
 
 	return bsonenc.Marshal(m)
 }
@@ -122,10 +124,22 @@ func (doc DAODelegatorsDoc) MarshalBSON() ([]byte, error) {
 	m["proposal_id"] = parsedKey[2]
 	m["height"] = doc.st.Height()
 	m["delegators"] = doc.di
+	m["delegator_accounts"] = daoDelegatorAccounts(doc.di)
 
 	return bsonenc.Marshal(m)
 }
 
+// daoDelegatorAccounts returns the accounts of the delegators, which the
+// delegations of an account are looked up by.
+func daoDelegatorAccounts(di []types.DelegatorInfo) []string {
+	accounts := make([]string, len(di))
+	for i := range di {
+		accounts[i] = di[i].Account().String()
+	}
+
+	return accounts
+}
+
 type DAOVotersDoc struct {
 	mongodbstorage.BaseDoc
 	st base.State
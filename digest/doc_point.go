@@ -4,11 +4,12 @@ import (
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	mongodbstorage "github.com/ProtoconNet/mitum-currency/v3/digest/mongodb"
 	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
+	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
+	"github.com/ProtoconNet/mitum-point/operation/point"
 	"github.com/ProtoconNet/mitum-point/state"
 	"github.com/ProtoconNet/mitum-point/types"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util/encoder"
-	crcystate "github.com/ProtoconNet/mitum-currency/v3/state"
 )
 
 type PointDoc struct {
@@ -91,3 +92,96 @@ func (doc PointBalanceDoc) MarshalBSON() ([]byte, error) {
 
 	return bsonenc.Marshal(m)
 }
+
+var (
+	PointActivityMint        = "mint"
+	PointActivityBurn        = "burn"
+	PointActivityTransferIn  = "transfer_in"
+	PointActivityTransferOut = "transfer_out"
+)
+
+// PointActivityDoc is a change of the point balance of an account made by an
+// operation. Points received by mint or transfer are counted as earned. The
+// amount is kept as decimal string with amount_len, like the token balances,
+// so any amount is stored without losing precision.
+type PointActivityDoc struct {
+	contract     string
+	address      string
+	counterparty string
+	kind         string
+	amount       common.Big
+	factHash     string
+	height       base.Height
+	index        uint64
+}
+
+func NewPointActivityDoc(
+	contract, address, counterparty, kind string,
+	amount common.Big,
+	factHash string,
+	height base.Height,
+	index uint64,
+) PointActivityDoc {
+	return PointActivityDoc{
+		contract:     contract,
+		address:      address,
+		counterparty: counterparty,
+		kind:         kind,
+		amount:       amount,
+		factHash:     factHash,
+		height:       height,
+		index:        index,
+	}
+}
+
+func (doc PointActivityDoc) MarshalBSON() ([]byte, error) {
+	amount, amountLen := amountSortKey(doc.amount)
+
+	return bsonenc.Marshal(map[string]interface{}{
+		"contract":     doc.contract,
+		"address":      doc.address,
+		"counterparty": doc.counterparty,
+		"kind":         doc.kind,
+		"earned":       doc.kind == PointActivityMint || doc.kind == PointActivityTransferIn,
+		"amount":       amount,
+		"amount_len":   amountLen,
+		"fact_hash":    doc.factHash,
+		"height":       doc.height,
+		"index":        doc.index,
+	})
+}
+
+// NewPointActivityDocs returns the activities of the point operation; the
+// other operations have none.
+func NewPointActivityDocs(op base.Operation, height base.Height, index uint64) []PointActivityDoc {
+	factHash := op.Fact().Hash().String()
+
+	switch fact := op.Fact().(type) {
+	case point.MintFact:
+		return []PointActivityDoc{
+			NewPointActivityDoc(fact.Contract().String(), fact.Receiver().String(), "",
+				PointActivityMint, fact.Amount(), factHash, height, index),
+		}
+	case point.BurnFact:
+		return []PointActivityDoc{
+			NewPointActivityDoc(fact.Contract().String(), fact.Target().String(), "",
+				PointActivityBurn, fact.Amount(), factHash, height, index),
+		}
+	case point.TransferFact:
+		return []PointActivityDoc{
+			NewPointActivityDoc(fact.Contract().String(), fact.Sender().String(), fact.Receiver().String(),
+				PointActivityTransferOut, fact.Amount(), factHash, height, index),
+			NewPointActivityDoc(fact.Contract().String(), fact.Receiver().String(), fact.Sender().String(),
+				PointActivityTransferIn, fact.Amount(), factHash, height, index),
+		}
+	case point.TransferFromFact:
+		return []PointActivityDoc{
+			NewPointActivityDoc(fact.Contract().String(), fact.Target().String(), fact.Receiver().String(),
+				PointActivityTransferOut, fact.Amount(), factHash, height, index),
+			NewPointActivityDoc(fact.Contract().String(), fact.Receiver().String(), fact.Target().String(),
+				PointActivityTransferIn, fact.Amount(), factHash, height, index),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPointActivityDocAmount(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount string
+	}{
+		{name: "small", amount: "12345"},
+		{name: "34 digits", amount: strings.Repeat("9", 34)},
+		{name: "over decimal128", amount: strings.Repeat("7", 40)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			amount, err := common.NewBigFromString(c.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := NewPointActivityDoc("contract", "address", "", PointActivityMint, amount, "fact", 3, 0).MarshalBSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var doc struct {
+				Amount    string `bson:"amount"`
+				AmountLen int    `bson:"amount_len"`
+				Earned    bool   `bson:"earned"`
+			}
+			if err := bson.Unmarshal(b, &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			if doc.Amount != c.amount || doc.AmountLen != len(c.amount) {
+				t.Errorf("expected %s(%d), but %s(%d)", c.amount, len(c.amount), doc.Amount, doc.AmountLen)
+			}
+
+			if !doc.Earned {
+				t.Error("mint should be earned")
+			}
+		})
+	}
+}
@@ -202,6 +202,44 @@ func (doc STOPartitionBalanceDoc) MarshalBSON() ([]byte, error) {
 	return bson.Marshal(m)
 }
 
+type STOPartitionControllersDoc struct {
+	mongodb.BaseDoc
+	st   base.State
+	ctls []base.Address
+}
+
+func NewSTOPartitionControllersDoc(st base.State, enc encoder.Encoder) (STOPartitionControllersDoc, error) {
+	ctls, err := ststo.StatePartitionControllersValue(st)
+	if err != nil {
+		return STOPartitionControllersDoc{}, err
+	}
+	b, err := mongodb.NewBaseDoc(nil, st, enc)
+	if err != nil {
+		return STOPartitionControllersDoc{}, err
+	}
+
+	return STOPartitionControllersDoc{
+		BaseDoc: b,
+		st:      st,
+		ctls:    ctls,
+	}, nil
+}
+
+func (doc STOPartitionControllersDoc) MarshalBSON() ([]byte, error) {
+	m, err := doc.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	parsedKey, err := crcystate.ParseStateKey(doc.st.Key(), ststo.STOPrefix, 4)
+	m["contract"] = parsedKey[1]
+	m["partition"] = parsedKey[2]
+	m["height"] = doc.st.Height()
+	m["controllers"] = doc.ctls
+
+	return bson.Marshal(m)
+}
+
 type STOOperatorHoldersDoc struct {
 	mongodb.BaseDoc
 	st  base.State
@@ -90,6 +90,8 @@ func (doc TimeStampItemDoc) MarshalBSON() ([]byte, error) {
 	m["contract"] = parsedKey[1]
 	m["project"] = doc.tsItem.ProjectID()
 	m["timestampidx"] = doc.tsItem.TimestampID()
+	m["request_timestamp"] = int64(doc.tsItem.RequestTimeStamp())
+	m["data_hash"] = doc.tsItem.Data()
 	m["height"] = doc.st.Height()
 	m["isItem"] = true
 
@@ -85,9 +85,54 @@ func (doc TokenBalanceDoc) MarshalBSON() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	balance, balanceLen := amountSortKey(doc.amount)
+
 	m["contract"] = stateKeys[1]
 	m["address"] = stateKeys[2]
+	m["balance"] = balance
+	m["balance_len"] = balanceLen
 	m["height"] = doc.st.Height()
 
 	return bsonenc.Marshal(m)
 }
+
+// amountSortKey returns the amount as decimal string and the length of it;
+// sorting by the length and then by the string orders the amounts without
+// losing precision.
+func amountSortKey(amount common.Big) (string, int) {
+	s := amount.String()
+
+	return s, len(s)
+}
+
+// TokenSupplyDoc is the supply of a token at the height where its design or
+// any balance changed.
+type TokenSupplyDoc struct {
+	contract    string
+	total       common.Big
+	circulating common.Big
+	holders     int64
+	height      base.Height
+}
+
+func NewTokenSupplyDoc(
+	contract string, total, circulating common.Big, holders int64, height base.Height,
+) TokenSupplyDoc {
+	return TokenSupplyDoc{
+		contract:    contract,
+		total:       total,
+		circulating: circulating,
+		holders:     holders,
+		height:      height,
+	}
+}
+
+func (doc TokenSupplyDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(map[string]interface{}{
+		"contract":           doc.contract,
+		"total_supply":       doc.total.String(),
+		"circulating_supply": doc.circulating.String(),
+		"holders":            doc.holders,
+		"height":             doc.height,
+	})
+}
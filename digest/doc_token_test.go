@@ -0,0 +1,33 @@
+package digest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+)
+
+func TestAmountSortKey(t *testing.T) {
+	amounts := []string{"10", "9", "0", strings.Repeat("9", 40), "100", "1" + strings.Repeat("0", 40), "11"}
+	expected := []string{"0", "9", "10", "11", "100", strings.Repeat("9", 40), "1" + strings.Repeat("0", 40)}
+
+	sort.Slice(amounts, func(i, j int) bool {
+		a, _ := common.NewBigFromString(amounts[i])
+		b, _ := common.NewBigFromString(amounts[j])
+
+		as, al := amountSortKey(a)
+		bs, bl := amountSortKey(b)
+		if al != bl {
+			return al < bl
+		}
+
+		return as < bs
+	})
+
+	for i := range expected {
+		if amounts[i] != expected[i] {
+			t.Fatalf("expected %v, but %v", expected, amounts)
+		}
+	}
+}
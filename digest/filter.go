@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/ProtoconNet/mitum2/base"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
@@ -40,40 +39,9 @@ func parseIdxFromPath(s string) (uint64, error) {
 	return i, nil
 }
 
-func buildNFTsFilterByAddress(address base.Address, offset string, reverse bool) (bson.D, error) {
-	filterA := bson.A{}
-
-	// filter fot matching address
-	filterAddress := bson.D{{"owner", bson.D{{"$in", []string{address.String()}}}}}
-	filterA = append(filterA, filterAddress)
-
-	// if offset exist, apply offset
-	if len(offset) > 0 {
-		if !reverse {
-			filterOffset := bson.D{
-				{"nftid", bson.D{{"$gt", offset}}},
-			}
-			filterA = append(filterA, filterOffset)
-			// if reverse true, lesser then offset height
-		} else {
-			filterHeight := bson.D{
-				{"nftid", bson.D{{"$lt", offset}}},
-			}
-			filterA = append(filterA, filterHeight)
-		}
-	}
-
-	filter := bson.D{}
-	if len(filterA) > 0 {
-		filter = bson.D{
-			{"$and", filterA},
-		}
-	}
-
-	return filter, nil
-}
-
-func buildNFTsFilterByContract(contract, facthash, offset string, reverse bool) (bson.D, error) {
+// buildNFTsFilterByContract filters the nfts of the collection; with end, the
+// nfts whose id is not less than end are excluded.
+func buildNFTsFilterByContract(contract, facthash, offset string, reverse bool, end *uint64) (bson.D, error) {
 	filterA := bson.A{}
 
 	// filter fot matching collection
@@ -103,6 +71,10 @@ func buildNFTsFilterByContract(contract, facthash, offset string, reverse bool)
 		}
 	}
 
+	if end != nil {
+		filterA = append(filterA, bson.D{{"nftid", bson.D{{"$lt", *end}}}})
+	}
+
 	if len(facthash) > 0 {
 		filterFactHash := bson.D{
 			{"facthash", bson.D{{"$in", []string{facthash}}}},
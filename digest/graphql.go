@@ -0,0 +1,1216 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// The graphql query is parsed and executed by the small engine below. It
+// supports the query operation with aliases, arguments, variables, fragments
+// and the introspection; directives, mutations and subscriptions are not
+// supported.
+
+var (
+	maxGraphQLDepth                    = 8
+	maxGraphQLIntrospectionDepth       = 16
+	maxGraphQLCost               int64 = 5000
+	defaultGraphQLListCost       int64 = 10
+	maxGraphQLAliases                  = 10
+	maxGraphQLQuerySize          int64 = 1 << 16
+)
+
+// gqlField is the field of selection set. The fragment spread and the inline
+// fragment are the fields named "..." until they are expanded; on is the type
+// condition of fragment.
+type gqlField struct {
+	alias      string
+	name       string
+	args       map[string]interface{}
+	selections []*gqlField
+	line       int
+	fragment   string
+	on         string
+}
+
+func (f *gqlField) key() string {
+	if len(f.alias) > 0 {
+		return f.alias
+	}
+
+	return f.name
+}
+
+type gqlOperation struct {
+	name       string
+	defaults   map[string]interface{}
+	selections []*gqlField
+}
+
+type gqlFragment struct {
+	on         string
+	selections []*gqlField
+}
+
+type gqlVariable string
+
+type gqlEnum string
+
+type gqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// gqlObject keeps the fields of a result in the order of the selection.
+type gqlObject []gqlEntry
+
+type gqlEntry struct {
+	key   string
+	value interface{}
+}
+
+func (o gqlObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(o[i].key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := mitumutil.MarshalJSON(o[i].value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+type gqlToken struct {
+	kind  byte // 'n' name, 'i' int, 'f' float, 's' string, 'p' punctuator, 0 eof
+	value string
+	line  int
+}
+
+type gqlParser struct {
+	src  string
+	pos  int
+	line int
+	tok  gqlToken
+}
+
+func parseGraphQL(src, operationName string) (*gqlOperation, error) {
+	p := &gqlParser{src: src, line: 1}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var operations []*gqlOperation
+	fragments := map[string]*gqlFragment{}
+	for p.tok.kind != 0 {
+		if p.tok.kind == 'n' && p.tok.value == "fragment" {
+			line := p.line
+
+			name, fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, found := fragments[name]; found {
+				return nil, errors.Errorf("line %d: fragment %q is already defined", line, name)
+			}
+
+			fragments[name] = fragment
+
+			continue
+		}
+
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, op)
+	}
+
+	var op *gqlOperation
+
+	switch {
+	case len(operations) < 1:
+		return nil, errors.Errorf("empty query")
+	case len(operationName) > 0:
+		for i := range operations {
+			if operations[i].name == operationName {
+				op = operations[i]
+
+				break
+			}
+		}
+
+		if op == nil {
+			return nil, errors.Errorf("unknown operation, %q", operationName)
+		}
+	case len(operations) > 1:
+		return nil, errors.Errorf("operationName is required for multiple operations")
+	default:
+		op = operations[0]
+	}
+
+	selections, err := expandGraphQLFragments(op.selections, fragments, nil)
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+
+	return op, nil
+}
+
+// expandGraphQLFragments replaces the fragments of selections with their
+// fields. The fields from the fragment keep the type condition of it, and the
+// fields of the same key are merged into one.
+func expandGraphQLFragments(
+	selections []*gqlField, fragments map[string]*gqlFragment, spreading []string,
+) ([]*gqlField, error) {
+	fields := make([]*gqlField, 0, len(selections))
+
+	for _, f := range selections {
+		if f.name != "..." {
+			g := *f
+
+			if len(f.selections) > 0 {
+				sub, err := expandGraphQLFragments(f.selections, fragments, spreading)
+				if err != nil {
+					return nil, err
+				}
+				g.selections = sub
+			}
+
+			fields = append(fields, &g)
+
+			continue
+		}
+
+		on, sub, nested := f.on, f.selections, spreading
+
+		if len(f.fragment) > 0 {
+			for i := range spreading {
+				if spreading[i] == f.fragment {
+					return nil, errors.Errorf("line %d: fragment %q spreads itself", f.line, f.fragment)
+				}
+			}
+
+			fragment, found := fragments[f.fragment]
+			if !found {
+				return nil, errors.Errorf("line %d: unknown fragment %q", f.line, f.fragment)
+			}
+
+			on, sub = fragment.on, fragment.selections
+			nested = append(append([]string{}, spreading...), f.fragment)
+		}
+
+		expanded, err := expandGraphQLFragments(sub, fragments, nested)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range expanded {
+			switch {
+			case len(on) < 1:
+			case len(e.on) < 1:
+				e.on = on
+			case e.on != on:
+				return nil, errors.Errorf("line %d: fragment on %s can not be spread in %s", e.line, e.on, on)
+			}
+
+			fields = append(fields, e)
+		}
+	}
+
+	return mergeGraphQLFields(fields)
+}
+
+// mergeGraphQLFields merges the selections of the fields of the same key; the
+// fields of the same key should be the same field with the same arguments.
+func mergeGraphQLFields(fields []*gqlField) ([]*gqlField, error) {
+	merged := make([]*gqlField, 0, len(fields))
+	keys := map[string]int{}
+
+	for _, f := range fields {
+		i, found := keys[f.key()]
+		if !found {
+			keys[f.key()] = len(merged)
+			merged = append(merged, f)
+
+			continue
+		}
+
+		m := *merged[i]
+
+		switch {
+		case m.name != f.name,
+			!reflect.DeepEqual(m.args, f.args),
+			len(m.on) > 0 && len(f.on) > 0 && m.on != f.on,
+			(len(m.selections) > 0) != (len(f.selections) > 0):
+			return nil, errors.Errorf("line %d: field %q conflicts with the field of line %d", f.line, f.key(), m.line)
+		}
+
+		if len(m.on) < 1 {
+			m.on = f.on
+		}
+
+		if len(f.selections) > 0 {
+			sub, err := mergeGraphQLFields(append(append([]*gqlField{}, m.selections...), f.selections...))
+			if err != nil {
+				return nil, err
+			}
+			m.selections = sub
+		}
+
+		merged[i] = &m
+	}
+
+	return merged, nil
+}
+
+func (p *gqlParser) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("syntax error at line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *gqlParser) next() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return p.scan()
+		}
+	}
+
+	p.tok = gqlToken{line: p.line}
+
+	return nil
+}
+
+func (p *gqlParser) scan() error {
+	start := p.pos
+	c := p.src[p.pos]
+
+	switch {
+	case strings.IndexByte("{}()[]:!$=@|&", c) >= 0:
+		p.pos++
+		p.tok = gqlToken{kind: 'p', value: string(c), line: p.line}
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = gqlToken{kind: 'p', value: "...", line: p.line}
+	case c == '_' || isGraphQLLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isGraphQLLetter(p.src[p.pos]) || isGraphQLDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = gqlToken{kind: 'n', value: p.src[start:p.pos], line: p.line}
+	case c == '-' || isGraphQLDigit(c):
+		kind := byte('i')
+		p.pos++
+		for p.pos < len(p.src) {
+			d := p.src[p.pos]
+			switch {
+			case isGraphQLDigit(d):
+			case d == '.' || d == 'e' || d == 'E':
+				kind = 'f'
+			case (d == '+' || d == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E'):
+			default:
+				p.tok = gqlToken{kind: kind, value: p.src[start:p.pos], line: p.line}
+
+				return nil
+			}
+			p.pos++
+		}
+		p.tok = gqlToken{kind: kind, value: p.src[start:p.pos], line: p.line}
+	case c == '"':
+		s, err := p.scanString()
+		if err != nil {
+			return err
+		}
+		p.tok = gqlToken{kind: 's', value: s, line: p.line}
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+
+		return p.errorf("unexpected character, %q", r)
+	}
+
+	return nil
+}
+
+func (p *gqlParser) scanString() (string, error) {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			return "", p.errorf("unterminated block string")
+		}
+
+		s := p.src[p.pos+3 : p.pos+3+end]
+		p.line += strings.Count(s, "\n")
+		p.pos += end + 6
+
+		return strings.TrimSpace(s), nil
+	}
+
+	end := p.pos + 1
+	for ; end < len(p.src); end++ {
+		switch p.src[end] {
+		case '\\':
+			end++
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '"':
+			s, err := strconv.Unquote(p.src[p.pos : end+1])
+			if err != nil {
+				return "", p.errorf("invalid string, %s", p.src[p.pos:end+1])
+			}
+			p.pos = end + 1
+
+			return s, nil
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func isGraphQLLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGraphQLDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *gqlParser) isPunct(v string) bool {
+	return p.tok.kind == 'p' && p.tok.value == v
+}
+
+func (p *gqlParser) expectPunct(v string) error {
+	if !p.isPunct(v) {
+		return p.errorf("expected %q, but %q", v, p.tok.value)
+	}
+
+	return p.next()
+}
+
+func (p *gqlParser) expectName() (string, error) {
+	if p.tok.kind != 'n' {
+		return "", p.errorf("expected name, but %q", p.tok.value)
+	}
+
+	name := p.tok.value
+
+	return name, p.next()
+}
+
+func (p *gqlParser) parseOperation() (*gqlOperation, error) {
+	op := &gqlOperation{defaults: map[string]interface{}{}}
+
+	if p.tok.kind == 'n' {
+		switch p.tok.value {
+		case "query":
+		default:
+			return nil, p.errorf("%q operation is not supported", p.tok.value)
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind == 'n' {
+			op.name = p.tok.value
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.isPunct("(") {
+			if err := p.parseVariableDefinitions(op); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.isPunct("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+
+	return op, nil
+}
+
+func (p *gqlParser) parseVariableDefinitions(op *gqlOperation) error {
+	if err := p.expectPunct("("); err != nil {
+		return err
+	}
+
+	for !p.isPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		if p.isPunct("=") {
+			if err := p.next(); err != nil {
+				return err
+			}
+
+			v, err := p.parseValue(true)
+			if err != nil {
+				return err
+			}
+			op.defaults[name] = v
+		}
+	}
+
+	return p.next()
+}
+
+// skipType consumes the type of variable; the arguments are checked by the
+// resolvers.
+func (p *gqlParser) skipType() error {
+	if p.isPunct("[") {
+		if err := p.next(); err != nil {
+			return err
+		}
+
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+
+	if p.isPunct("!") {
+		return p.next()
+	}
+
+	return nil
+}
+
+func (p *gqlParser) parseSelectionSet() ([]*gqlField, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []*gqlField
+	for !p.isPunct("}") {
+		if p.tok.kind == 0 {
+			return nil, p.errorf("unexpected end of query")
+		}
+
+		if p.isPunct("...") {
+			f, err := p.parseFragmentSpread()
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, f)
+
+			continue
+		}
+
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) < 1 {
+		return nil, p.errorf("empty selection set")
+	}
+
+	return fields, p.next()
+}
+
+func (p *gqlParser) parseFragment() (string, *gqlFragment, error) {
+	if err := p.next(); err != nil {
+		return "", nil, err
+	}
+
+	name, err := p.expectName()
+	switch {
+	case err != nil:
+		return "", nil, err
+	case name == "on":
+		return "", nil, p.errorf("fragment name must not be \"on\"")
+	}
+
+	on, err := p.parseTypeCondition()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if p.isPunct("@") {
+		return "", nil, p.errorf("directives are not supported")
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, &gqlFragment{on: on, selections: selections}, nil
+}
+
+func (p *gqlParser) parseTypeCondition() (string, error) {
+	if p.tok.kind != 'n' || p.tok.value != "on" {
+		return "", p.errorf("expected \"on\", but %q", p.tok.value)
+	}
+
+	if err := p.next(); err != nil {
+		return "", err
+	}
+
+	return p.expectName()
+}
+
+// parseFragmentSpread parses the fragment spread, "...Name", or the inline
+// fragment, "... on Type { }".
+func (p *gqlParser) parseFragmentSpread() (*gqlField, error) {
+	f := &gqlField{name: "...", line: p.line}
+
+	if err := p.expectPunct("..."); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.tok.kind == 'n' && p.tok.value != "on":
+		f.fragment = p.tok.value
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	case p.tok.kind == 'n':
+		on, err := p.parseTypeCondition()
+		if err != nil {
+			return nil, err
+		}
+		f.on = on
+
+		fallthrough
+	default:
+		if p.isPunct("@") {
+			return nil, p.errorf("directives are not supported")
+		}
+
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		f.selections = selections
+	}
+
+	if p.isPunct("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	return f, nil
+}
+
+func (p *gqlParser) parseField() (*gqlField, error) {
+	f := &gqlField{line: p.line}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isPunct(":") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		f.alias = name
+		if name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	f.name = name
+
+	if p.isPunct("(") {
+		if f.args, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	if p.isPunct("{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (p *gqlParser) parseArguments() (map[string]interface{}, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	args := map[string]interface{}{}
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+
+		v, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+
+		args[name] = v
+	}
+
+	return args, p.next()
+}
+
+func (p *gqlParser) parseValue(isConst bool) (interface{}, error) {
+	tok := p.tok
+
+	switch {
+	case tok.kind == 'p' && tok.value == "$":
+		if isConst {
+			return nil, p.errorf("variable is not allowed here")
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+
+		return gqlVariable(name), err
+	case tok.kind == 'p' && tok.value == "[":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		l := []interface{}{}
+		for !p.isPunct("]") {
+			v, err := p.parseValue(isConst)
+			if err != nil {
+				return nil, err
+			}
+
+			l = append(l, v)
+		}
+
+		return l, p.next()
+	case tok.kind == 'p' && tok.value == "{":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		m := map[string]interface{}{}
+		for !p.isPunct("}") {
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+
+			v, err := p.parseValue(isConst)
+			if err != nil {
+				return nil, err
+			}
+
+			m[name] = v
+		}
+
+		return m, p.next()
+	case tok.kind == 'i':
+		i, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid int, %q", tok.value)
+		}
+
+		return i, p.next()
+	case tok.kind == 'f':
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float, %q", tok.value)
+		}
+
+		return f, p.next()
+	case tok.kind == 's':
+		return tok.value, p.next()
+	case tok.kind == 'n':
+		var v interface{}
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = gqlEnum(tok.value)
+		}
+
+		return v, p.next()
+	default:
+		return nil, p.errorf("unexpected %q", tok.value)
+	}
+}
+
+// gqlArgs is the arguments of field with the variables resolved.
+type gqlArgs map[string]interface{}
+
+func (a gqlArgs) String(name string) (string, error) {
+	switch t := a[name].(type) {
+	case string:
+		return t, nil
+	case nil:
+		return "", errors.Errorf("argument %q is required", name)
+	default:
+		return "", errors.Errorf("argument %q must be a string", name)
+	}
+}
+
+func (a gqlArgs) OptionalString(name string) (string, error) {
+	if a[name] == nil {
+		return "", nil
+	}
+
+	return a.String(name)
+}
+
+func (a gqlArgs) Int(name string, d int64) (int64, error) {
+	switch t := a[name].(type) {
+	case nil:
+		return d, nil
+	case int64:
+		return t, nil
+	case float64: // NOTE numbers of the json variables are decoded as float64
+		if t != float64(int64(t)) {
+			return 0, errors.Errorf("argument %q must be an integer", name)
+		}
+
+		return int64(t), nil
+	default:
+		return 0, errors.Errorf("argument %q must be an integer", name)
+	}
+}
+
+func (a gqlArgs) Bool(name string) (bool, error) {
+	switch t := a[name].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return t, nil
+	default:
+		return false, errors.Errorf("argument %q must be a boolean", name)
+	}
+}
+
+// Limit returns the limit argument bounded by maxLimit.
+func (a gqlArgs) Limit() (int64, error) {
+	limit, err := a.Int("limit", defaultGraphQLListCost)
+
+	switch {
+	case err != nil:
+		return 0, err
+	case limit < 1:
+		return 0, errors.Errorf("argument \"limit\" must be over zero")
+	case limit > maxLimit:
+		return maxLimit, nil
+	default:
+		return limit, nil
+	}
+}
+
+func resolveGraphQLValue(v interface{}, variables map[string]interface{}) interface{} {
+	switch t := v.(type) {
+	case gqlVariable:
+		return variables[string(t)]
+	case gqlEnum:
+		return string(t)
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i := range t {
+			l[i] = resolveGraphQLValue(t[i], variables)
+		}
+
+		return l
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k := range t {
+			m[k] = resolveGraphQLValue(t[k], variables)
+		}
+
+		return m
+	default:
+		return v
+	}
+}
+
+type gqlResolver func(parent interface{}, args gqlArgs) (interface{}, error)
+
+// gqlFieldDef defines the field of object type. The field without type is a
+// leaf; its value is marshaled as it is and its type is scalar, JSON by
+// default. The cost is the weight of the resolver against the cost limit; zero
+// cost counts as 1. The list field has the limit argument besides args and
+// the list is cut by it.
+type gqlFieldDef struct {
+	typ     string
+	list    bool
+	scalar  string
+	cost    int64
+	args    []gqlArgDef
+	resolve gqlResolver
+}
+
+// gqlArgDef is the argument of field; typ is in the notation of graphql like
+// "String!".
+type gqlArgDef struct {
+	name string
+	typ  string
+}
+
+var gqlLimitArg = gqlArgDef{name: "limit", typ: "Int"}
+
+// arguments returns the arguments of field with the limit of list.
+func (def gqlFieldDef) arguments(meta bool) []gqlArgDef {
+	if !def.list || meta {
+		return def.args
+	}
+
+	for i := range def.args {
+		if def.args[i].name == gqlLimitArg.name {
+			return def.args
+		}
+	}
+
+	return append(append([]gqlArgDef{}, def.args...), gqlLimitArg)
+}
+
+func (def gqlFieldDef) hasArgument(name string, meta bool) bool {
+	args := def.arguments(meta)
+	for i := range args {
+		if args[i].name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gqlListLimit is the number of items of the list field; the selections under
+// the list are counted as many as it.
+func gqlListLimit(args gqlArgs) int64 {
+	n := defaultGraphQLListCost
+	if i, err := args.Int("limit", n); err == nil && i > 0 {
+		n = min(i, maxLimit)
+	}
+
+	return n
+}
+
+type gqlSchema map[string]map[string]gqlFieldDef
+
+// field returns the field definition of the type. The introspection types
+// and the introspection fields of Query are served from gqlMetaSchema.
+func (s gqlSchema) field(typ, name string) (gqlFieldDef, bool) {
+	if isGraphQLMetaType(typ) {
+		def, found := gqlMetaSchema[typ][name]
+
+		return def, found
+	}
+
+	if typ == "Query" {
+		switch name {
+		case "__schema":
+			return gqlFieldDef{typ: "__Schema", resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) {
+				return s, nil
+			}}, true
+		case "__type":
+			return gqlFieldDef{
+				typ:  "__Type",
+				args: []gqlArgDef{{name: "name", typ: "String!"}},
+				resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+					name, err := args.String("name")
+					if err != nil {
+						return nil, err
+					}
+
+					return s.namedType(name)
+				},
+			}, true
+		}
+	}
+
+	def, found := s[typ][name]
+
+	return def, found
+}
+
+// validate checks the fields of selections against the schema and the depth,
+// the alias and the cost limits. Every field costs as much as its weight and
+// the selections under the list field are counted as many as the limit
+// argument. The same field can be selected up to maxGraphQLAliases times in a
+// selection set. The introspection types cost nothing, but their fields can
+// not be aliased, so the result is bounded by the schema.
+func (s gqlSchema) validate(
+	typ string, selections []*gqlField, variables map[string]interface{}, depth int,
+) (int64, error) {
+	meta := isGraphQLMetaType(typ)
+
+	switch {
+	case meta && depth > maxGraphQLIntrospectionDepth:
+		return 0, errors.Errorf("introspection query is too deep; max depth is %d", maxGraphQLIntrospectionDepth)
+	case !meta && depth > maxGraphQLDepth:
+		return 0, errors.Errorf("query is too deep; max depth is %d", maxGraphQLDepth)
+	}
+
+	aliases := maxGraphQLAliases
+	if meta {
+		aliases = 1
+	}
+
+	var cost int64
+	selected := map[string]int{}
+	for _, f := range selections {
+		selected[f.name]++
+		if selected[f.name] > aliases {
+			return 0, errors.Errorf("line %d: field %q is selected too many times; max is %d",
+				f.line, f.name, aliases)
+		}
+
+		if len(f.on) > 0 && f.on != typ {
+			return 0, errors.Errorf("line %d: fragment on %s can not be spread in %s", f.line, f.on, typ)
+		}
+
+		if f.name == "__typename" {
+			if !meta {
+				cost++
+			}
+
+			continue
+		}
+
+		def, found := s.field(typ, f.name)
+		switch {
+		case !found:
+			return 0, errors.Errorf("line %d: unknown field %q on type %s", f.line, f.name, typ)
+		case len(def.typ) < 1 && len(f.selections) > 0:
+			return 0, errors.Errorf("line %d: field %q of type %s must not have a selection", f.line, f.name, typ)
+		case len(def.typ) > 0 && len(f.selections) < 1:
+			return 0, errors.Errorf("line %d: field %q of type %s must have a selection", f.line, f.name, typ)
+		}
+
+		for name := range f.args {
+			if !def.hasArgument(name, meta) {
+				return 0, errors.Errorf("line %d: unknown argument %q on field %q of type %s", f.line, name, f.name, typ)
+			}
+		}
+
+		switch {
+		case meta:
+		case def.cost > 0:
+			cost += def.cost
+		default:
+			cost++
+		}
+
+		if len(def.typ) > 0 {
+			c, err := s.validate(def.typ, f.selections, variables, depth+1)
+			if err != nil {
+				return 0, err
+			}
+
+			if def.list {
+				c *= gqlListLimit(gqlArgs{"limit": resolveGraphQLValue(f.args["limit"], variables)})
+			}
+
+			cost += c
+		}
+
+		if cost > maxGraphQLCost {
+			return 0, errors.Errorf("query is too expensive; max cost is %d", maxGraphQLCost)
+		}
+	}
+
+	return cost, nil
+}
+
+type gqlExecutor struct {
+	schema    gqlSchema
+	variables map[string]interface{}
+	errs      []gqlError
+}
+
+func (e *gqlExecutor) execute(typ string, parent interface{}, selections []*gqlField, path []interface{}) gqlObject {
+	o := make(gqlObject, 0, len(selections))
+
+	for _, f := range selections {
+		if f.name == "__typename" {
+			o = append(o, gqlEntry{key: f.key(), value: typ})
+
+			continue
+		}
+
+		fpath := append(append([]interface{}{}, path...), f.key())
+		o = append(o, gqlEntry{key: f.key(), value: e.executeField(typ, parent, f, fpath)})
+	}
+
+	return o
+}
+
+func (e *gqlExecutor) executeField(typ string, parent interface{}, f *gqlField, path []interface{}) interface{} {
+	def, _ := e.schema.field(typ, f.name)
+
+	args := gqlArgs{}
+	for k := range f.args {
+		args[k] = resolveGraphQLValue(f.args[k], e.variables)
+	}
+
+	v, err := def.resolve(parent, args)
+	switch {
+	case err == nil:
+	case errors.Is(err, mitumutil.ErrNotFound):
+		return nil
+	default:
+		e.errs = append(e.errs, gqlError{Message: err.Error(), Path: path})
+
+		return nil
+	}
+
+	if len(def.typ) < 1 || v == nil {
+		return v
+	}
+
+	if !def.list {
+		return e.execute(def.typ, v, f.selections, path)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		e.errs = append(e.errs, gqlError{Message: "list expected", Path: path})
+
+		return nil
+	}
+
+	if !isGraphQLMetaType(typ) {
+		limit, err := args.Limit()
+		if err != nil {
+			e.errs = append(e.errs, gqlError{Message: err.Error(), Path: path})
+
+			return nil
+		}
+
+		if int64(rv.Len()) > limit {
+			rv = rv.Slice(0, int(limit))
+		}
+	}
+
+	l := make([]interface{}, rv.Len())
+	for i := range l {
+		ipath := append(append([]interface{}{}, path...), i)
+		l[i] = e.execute(def.typ, rv.Index(i).Interface(), f.selections, ipath)
+	}
+
+	return l
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type GraphQLResponse struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []gqlError  `json:"errors,omitempty"`
+}
+
+// ExecuteGraphQL runs the query of request against the Query type of schema.
+// The error is returned when the query is invalid; the errors from resolvers
+// are collected in the response.
+func ExecuteGraphQL(schema gqlSchema, req GraphQLRequest) (*GraphQLResponse, error) {
+	op, err := parseGraphQL(req.Query, req.OperationName)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]interface{}{}
+	for k := range op.defaults {
+		variables[k] = op.defaults[k]
+	}
+	for k := range req.Variables {
+		variables[k] = req.Variables[k]
+	}
+
+	if _, err := schema.validate("Query", op.selections, variables, 1); err != nil {
+		return nil, err
+	}
+
+	e := &gqlExecutor{schema: schema, variables: variables}
+	data := e.execute("Query", nil, op.selections, nil)
+
+	return &GraphQLResponse{Data: data, Errors: e.errs}, nil
+}
@@ -0,0 +1,303 @@
+package digest
+
+import (
+	"sort"
+	"strings"
+
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+)
+
+// The introspection of the schema is served by the types of gqlMetaSchema
+// like the other types; the types are named with the "__" prefix. Every type
+// of the schema is an object or a scalar, so interfaces, unions, enums and
+// input objects are always empty.
+
+const gqlDefaultScalar = "JSON"
+
+var gqlScalarDescriptions = map[string]string{
+	gqlDefaultScalar: "The value as it is in the HAL responses of the same resource.",
+}
+
+var gqlMetaSchema gqlSchema
+
+func init() {
+	gqlMetaSchema = newGraphQLMetaSchema()
+}
+
+func isGraphQLMetaType(typ string) bool {
+	return strings.HasPrefix(typ, "__")
+}
+
+// gqlType is the type in the introspection; the list and the non null type
+// wrap ofType.
+type gqlType struct {
+	schema gqlSchema
+	kind   string
+	name   string
+	ofType *gqlType
+}
+
+type gqlMetaField struct {
+	schema gqlSchema
+	name   string
+	def    gqlFieldDef
+	meta   bool
+}
+
+type gqlInputValue struct {
+	schema gqlSchema
+	arg    gqlArgDef
+}
+
+// typeNotation is the type of the field in the notation of graphql; the items
+// of list are not null.
+func (def gqlFieldDef) typeNotation() string {
+	switch {
+	case len(def.typ) > 0 && def.list:
+		return "[" + def.typ + "!]"
+	case len(def.typ) > 0:
+		return def.typ
+	case len(def.scalar) > 0:
+		return def.scalar
+	default:
+		return gqlDefaultScalar
+	}
+}
+
+// objectType returns the fields of the object type including the
+// introspection types.
+func (s gqlSchema) objectType(name string) (map[string]gqlFieldDef, bool) {
+	if isGraphQLMetaType(name) {
+		fields, found := gqlMetaSchema[name]
+
+		return fields, found
+	}
+
+	fields, found := s[name]
+
+	return fields, found
+}
+
+// scalars collects the scalar types of the fields and the arguments.
+func (s gqlSchema) scalars() map[string]struct{} {
+	m := map[string]struct{}{}
+
+	add := func(notation string) {
+		name := strings.Trim(notation, "[]!")
+		if _, found := s.objectType(name); !found {
+			m[name] = struct{}{}
+		}
+	}
+
+	for _, schema := range []gqlSchema{s, gqlMetaSchema} {
+		for _, fields := range schema {
+			for _, def := range fields {
+				add(def.typeNotation())
+
+				for _, arg := range def.arguments(false) {
+					add(arg.typ)
+				}
+			}
+		}
+	}
+
+	return m
+}
+
+// typeOf parses the type notation like "[String!]!".
+func (s gqlSchema) typeOf(notation string) *gqlType {
+	switch {
+	case strings.HasSuffix(notation, "!"):
+		return &gqlType{schema: s, kind: "NON_NULL", ofType: s.typeOf(notation[:len(notation)-1])}
+	case strings.HasPrefix(notation, "[") && strings.HasSuffix(notation, "]"):
+		return &gqlType{schema: s, kind: "LIST", ofType: s.typeOf(notation[1 : len(notation)-1])}
+	}
+
+	kind := "SCALAR"
+	if _, found := s.objectType(notation); found {
+		kind = "OBJECT"
+	}
+
+	return &gqlType{schema: s, kind: kind, name: notation}
+}
+
+func (s gqlSchema) namedType(name string) (*gqlType, error) {
+	if _, found := s.objectType(name); !found {
+		if _, found := s.scalars()[name]; !found {
+			return nil, mitumutil.ErrNotFound.Errorf("type, %q", name)
+		}
+	}
+
+	return s.typeOf(name), nil
+}
+
+// types returns every named type of the schema in order of name.
+func (s gqlSchema) types() []*gqlType {
+	var names []string
+
+	for _, schema := range []gqlSchema{s, gqlMetaSchema} {
+		for name := range schema {
+			names = append(names, name)
+		}
+	}
+
+	for name := range s.scalars() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	types := make([]*gqlType, len(names))
+	for i := range names {
+		types[i] = s.typeOf(names[i])
+	}
+
+	return types
+}
+
+func (t *gqlType) fields() []gqlMetaField {
+	defs, _ := t.schema.objectType(t.name)
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fields := make([]gqlMetaField, len(names))
+	for i := range names {
+		fields[i] = gqlMetaField{
+			schema: t.schema,
+			name:   names[i],
+			def:    defs[names[i]],
+			meta:   isGraphQLMetaType(t.name),
+		}
+	}
+
+	return fields
+}
+
+func (f gqlMetaField) args() []gqlInputValue {
+	args := f.def.arguments(f.meta)
+
+	values := make([]gqlInputValue, len(args))
+	for i := range args {
+		values[i] = gqlInputValue{schema: f.schema, arg: args[i]}
+	}
+
+	return values
+}
+
+func newGraphQLMetaSchema() gqlSchema {
+	none := func(interface{}, gqlArgs) (interface{}, error) { return nil, nil }
+	no := func(interface{}, gqlArgs) (interface{}, error) { return false, nil }
+	includeDeprecated := []gqlArgDef{{name: "includeDeprecated", typ: "Boolean"}}
+
+	objectOnly := func(f func(*gqlType) interface{}) gqlResolver {
+		return func(p interface{}, _ gqlArgs) (interface{}, error) {
+			if t := p.(*gqlType); t.kind == "OBJECT" {
+				return f(t), nil
+			}
+
+			return nil, nil
+		}
+	}
+
+	return gqlSchema{
+		"__Schema": {
+			"description": {scalar: "String", resolve: none},
+			"types": {typ: "__Type", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlSchema).types(), nil
+			}},
+			"queryType": {typ: "__Type", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlSchema).typeOf("Query"), nil
+			}},
+			"mutationType":     {typ: "__Type", resolve: none},
+			"subscriptionType": {typ: "__Type", resolve: none},
+			"directives": {typ: "__Directive", list: true, resolve: func(interface{}, gqlArgs) (interface{}, error) {
+				return []interface{}{}, nil
+			}},
+		},
+		"__Type": {
+			"kind": {scalar: "String!", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(*gqlType).kind, nil
+			}},
+			"name": {scalar: "String", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				if name := p.(*gqlType).name; len(name) > 0 {
+					return name, nil
+				}
+
+				return nil, nil
+			}},
+			"description": {scalar: "String", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				if d, found := gqlScalarDescriptions[p.(*gqlType).name]; found && p.(*gqlType).kind == "SCALAR" {
+					return d, nil
+				}
+
+				return nil, nil
+			}},
+			"specifiedByURL": {scalar: "String", resolve: none},
+			"fields": {typ: "__Field", list: true, args: includeDeprecated, resolve: objectOnly(func(t *gqlType) interface{} {
+				return t.fields()
+			})},
+			"interfaces": {typ: "__Type", list: true, resolve: objectOnly(func(*gqlType) interface{} {
+				return []*gqlType{}
+			})},
+			"possibleTypes": {typ: "__Type", list: true, resolve: none},
+			"enumValues":    {typ: "__EnumValue", list: true, args: includeDeprecated, resolve: none},
+			"inputFields":   {typ: "__InputValue", list: true, args: includeDeprecated, resolve: none},
+			"ofType": {typ: "__Type", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				if t := p.(*gqlType).ofType; t != nil {
+					return t, nil
+				}
+
+				return nil, nil
+			}},
+			"isOneOf": {scalar: "Boolean", resolve: none},
+		},
+		"__Field": {
+			"name": {scalar: "String!", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlMetaField).name, nil
+			}},
+			"description": {scalar: "String", resolve: none},
+			"args": {typ: "__InputValue", list: true, args: includeDeprecated, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlMetaField).args(), nil
+			}},
+			"type": {typ: "__Type", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				f := p.(gqlMetaField)
+
+				return f.schema.typeOf(f.def.typeNotation()), nil
+			}},
+			"isDeprecated":      {scalar: "Boolean!", resolve: no},
+			"deprecationReason": {scalar: "String", resolve: none},
+		},
+		"__InputValue": {
+			"name": {scalar: "String!", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlInputValue).arg.name, nil
+			}},
+			"description": {scalar: "String", resolve: none},
+			"type": {typ: "__Type", resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				v := p.(gqlInputValue)
+
+				return v.schema.typeOf(v.arg.typ), nil
+			}},
+			"defaultValue":      {scalar: "String", resolve: none},
+			"isDeprecated":      {scalar: "Boolean!", resolve: no},
+			"deprecationReason": {scalar: "String", resolve: none},
+		},
+		"__EnumValue": {
+			"name":              {scalar: "String!", resolve: none},
+			"description":       {scalar: "String", resolve: none},
+			"isDeprecated":      {scalar: "Boolean!", resolve: no},
+			"deprecationReason": {scalar: "String", resolve: none},
+		},
+		"__Directive": {
+			"name":         {scalar: "String!", resolve: none},
+			"description":  {scalar: "String", resolve: none},
+			"locations":    {scalar: "[String!]!", resolve: none},
+			"args":         {typ: "__InputValue", list: true, args: includeDeprecated, resolve: none},
+			"isRepeatable": {scalar: "Boolean!", resolve: no},
+		},
+	}
+}
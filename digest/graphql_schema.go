@@ -0,0 +1,826 @@
+package digest
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	credentialtypes "github.com/ProtoconNet/mitum-credential/types"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	daostate "github.com/ProtoconNet/mitum-dao/state"
+	daotypes "github.com/ProtoconNet/mitum-dao/types"
+	"github.com/ProtoconNet/mitum-nft/v2/types"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type gqlBlock struct {
+	height    mitumbase.Height
+	manifest  mitumbase.Manifest
+	confirmed string
+}
+
+type gqlCredential struct {
+	credential credentialtypes.Credential
+	isActive   bool
+}
+
+type gqlProposal struct {
+	contract string
+	id       string
+	proposal daostate.ProposalStateValue
+}
+
+type gqlAmount struct {
+	key    string
+	amount string
+}
+
+type gqlTemplate struct {
+	id       string
+	template credentialtypes.Template
+}
+
+// The weights of resolvers against the cost limit of query. A scan reads a
+// page or a small collection of the contract, and an aggregation reads every
+// state of the contract or of the proposal.
+var (
+	gqlLookupCost      int64 = 5
+	gqlScanCost        int64 = 20
+	gqlAggregationCost int64 = 200
+)
+
+// newGraphQLSchema builds the types of graphql schema. The values of module
+// designs and states are leaves and marshaled as they are in the HAL
+// responses; the results built by the digest are typed objects, and the items
+// of the module states in lists have typed fields besides the whole value.
+func (hd *Handlers) newGraphQLSchema() gqlSchema {
+	st := hd.database
+
+	contractArg := func(args gqlArgs) (interface{}, error) {
+		return args.String("contract")
+	}
+
+	contractArgs := []gqlArgDef{{name: "contract", typ: "String!"}}
+	pageArgs := []gqlArgDef{{name: "offset", typ: "String"}, {name: "reverse", typ: "Boolean"}}
+	arg := func(name, typ string) []gqlArgDef {
+		return []gqlArgDef{{name: name, typ: typ}}
+	}
+
+	return gqlSchema{
+		"Query": {
+			"lastBlock": {typ: "Block", resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) {
+				return gqlLoadBlock(st, st.LastBlock())
+			}},
+			"block": {typ: "Block", args: arg("height", "Int!"), resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				height, err := args.Int("height", -1)
+				switch {
+				case err != nil:
+					return nil, err
+				case height < 0:
+					return nil, errors.Errorf("argument \"height\" is required")
+				}
+
+				return gqlLoadBlock(st, mitumbase.Height(height))
+			}},
+			"operation": {typ: "Operation", args: arg("hash", "String!"), resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				h, err := args.String("hash")
+				if err != nil {
+					return nil, err
+				}
+
+				va, found, err := st.Operation(valuehash.NewBytesFromString(h), true)
+				switch {
+				case err != nil:
+					return nil, err
+				case !found:
+					return nil, mitumutil.ErrNotFound.Errorf("operation, %s", h)
+				}
+
+				return va, nil
+			}},
+			"account": {typ: "Account", args: arg("address", "String!"), resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				s, err := args.String("address")
+				if err != nil {
+					return nil, err
+				}
+
+				address, err := mitumbase.ParseStringAddress(s)
+				if err != nil {
+					return nil, err
+				}
+
+				va, found, err := st.Account(address)
+				switch {
+				case err != nil:
+					return nil, err
+				case !found:
+					return nil, mitumutil.ErrNotFound.Errorf("account, %s", s)
+				}
+
+				return va, nil
+			}},
+			"contracts": {typ: "Contract", list: true, args: append(arg("model", "String"), pageArgs...), resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				model, err := args.OptionalString("model")
+				if err != nil {
+					return nil, err
+				}
+
+				if len(model) > 0 && !IsContractModel(model) {
+					return nil, errors.Errorf("invalid model, %q; available: %v", model, contractModelNames)
+				}
+
+				offset, err := args.OptionalString("offset")
+				if err != nil {
+					return nil, err
+				}
+
+				reverse, err := args.Bool("reverse")
+				if err != nil {
+					return nil, err
+				}
+
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				return Contracts(st, model, offset, reverse, limit, nil)
+			}},
+			"search": {typ: "SearchResult", list: true, cost: gqlAggregationCost, args: arg("q", "String!"), resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				q, err := args.String("q")
+				if err != nil {
+					return nil, err
+				}
+
+				return Search(st, q)
+			}},
+			"nft":        {typ: "NFTModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"credential": {typ: "CredentialModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"timestamp":  {typ: "TimeStampModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"token":      {typ: "TokenModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"point":      {typ: "PointModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"dao":        {typ: "DAOModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+			"sto":        {typ: "STOModel", args: contractArgs, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) { return contractArg(args) }},
+		},
+		"Block": {
+			"height": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlBlock).height, nil
+			}},
+			"hash": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlBlock).manifest.Hash().String(), nil
+			}},
+			"confirmedAt": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlBlock).confirmed, nil
+			}},
+			"manifest": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlBlock).manifest, nil
+			}},
+			"operations": {typ: "Operation", list: true, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				var operations []currencydigest.OperationValue
+				if err := st.Operations(
+					bson.M{"height": p.(gqlBlock).height},
+					true,
+					false,
+					limit,
+					func(_ mitumutil.Hash, va currencydigest.OperationValue) (bool, error) {
+						operations = append(operations, va)
+
+						return true, nil
+					},
+				); err != nil {
+					return nil, err
+				}
+
+				return operations, nil
+			}},
+		},
+		"Operation": {
+			"hash": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).Operation().Fact().Hash().String(), nil
+			}},
+			"height": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).Height(), nil
+			}},
+			"index": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).Index(), nil
+			}},
+			"confirmedAt": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).ConfirmedAt(), nil
+			}},
+			"inState": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).InState(), nil
+			}},
+			"reason": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).Reason(), nil
+			}},
+			"operation": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.OperationValue).Operation(), nil
+			}},
+		},
+		"Account": {
+			"address": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.AccountValue).Account().Address().String(), nil
+			}},
+			"keys": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.AccountValue).Account().Keys(), nil
+			}},
+			"height": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.AccountValue).Height(), nil
+			}},
+			"balances": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(currencydigest.AccountValue).Balance(), nil
+			}},
+			"operations": {typ: "Operation", list: true, args: pageArgs, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				offset, err := args.OptionalString("offset")
+				if err != nil {
+					return nil, err
+				}
+
+				reverse, err := args.Bool("reverse")
+				if err != nil {
+					return nil, err
+				}
+
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				var operations []currencydigest.OperationValue
+				if err := st.OperationsByAddress(
+					p.(currencydigest.AccountValue).Account().Address(),
+					true,
+					reverse,
+					offset,
+					limit,
+					func(_ mitumutil.Hash, va currencydigest.OperationValue) (bool, error) {
+						operations = append(operations, va)
+
+						return true, nil
+					},
+				); err != nil {
+					return nil, err
+				}
+
+				return operations, nil
+			}},
+			"contract": {typ: "Contract", cost: gqlLookupCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				address := p.(currencydigest.AccountValue).Account().Address().String()
+
+				contracts, err := contractAccounts(st, bson.D{{"address", address}}, nil)
+				if err != nil || len(contracts) < 1 {
+					return nil, err
+				}
+
+				if err := fillContractModels(st, contracts, nil); err != nil {
+					return nil, err
+				}
+
+				return contracts[0], nil
+			}},
+			"portfolio": {cost: gqlAggregationCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				now, err := LastBlockTime(st)
+				if err != nil {
+					return nil, err
+				}
+
+				return AccountPortfolio(st, p.(currencydigest.AccountValue).Account().Address().String(), now)
+			}},
+		},
+		"Contract": {
+			"address": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractInfo).Address, nil }},
+			"owner":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractInfo).Owner, nil }},
+			"active":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractInfo).Active, nil }},
+			"height":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractInfo).Height, nil }},
+			"models":  {typ: "ContractModelInfo", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractInfo).Models, nil }},
+		},
+		"ContractModelInfo": {
+			"model":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractModelInfo).Model, nil }},
+			"height": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(ContractModelInfo).Height, nil }},
+		},
+		"SearchResult": {
+			"type":     {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).Type, nil }},
+			"id":       {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).ID, nil }},
+			"contract": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).Contract, nil }},
+			"template": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).Template, nil }},
+			"height":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).Height, nil }},
+			"models":   {typ: "ContractModelInfo", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(SearchResult).Models, nil }},
+		},
+		"NFTModel": {
+			"contract": {resolve: gqlContract},
+			"collection": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return NFTCollection(st, p.(string))
+			}},
+			"count": {cost: gqlScanCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return NFTCountByCollection(st, p.(string), "", nil)
+			}},
+			"nft": {args: arg("id", "Int!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				id, err := args.Int("id", -1)
+				switch {
+				case err != nil:
+					return nil, err
+				case id < 0:
+					return nil, errors.Errorf("argument \"id\" is required")
+				}
+
+				return NFT(st, p.(string), strconv.FormatInt(id, 10))
+			}},
+			"nfts": {typ: "NFT", list: true, args: pageArgs, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				offset, err := args.OptionalString("offset")
+				if err != nil {
+					return nil, err
+				}
+
+				reverse, err := args.Bool("reverse")
+				if err != nil {
+					return nil, err
+				}
+
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				nfts := []types.NFT{}
+				if err := NFTsByCollection(st, p.(string), "", offset, reverse, limit, nil,
+					func(nft types.NFT, _ mitumbase.State) (bool, error) {
+						nfts = append(nfts, nft)
+
+						return true, nil
+					},
+				); err != nil {
+					return nil, err
+				}
+
+				return nfts, nil
+			}},
+		},
+		"NFT": {
+			"id": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(types.NFT).ID(), nil }},
+			"owner": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(types.NFT).Owner().String(), nil
+			}},
+			"creators": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				as := p.(types.NFT).Creators().Addresses()
+
+				creators := make([]string, len(as))
+				for i := range as {
+					creators[i] = as[i].String()
+				}
+
+				return creators, nil
+			}},
+			"nft": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(types.NFT), nil }},
+		},
+		"CredentialModel": {
+			"contract": {resolve: gqlContract},
+			"service": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return CredentialService(st, p.(string))
+			}},
+			"templates": {typ: "Template", list: true, cost: gqlScanCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				var templates []gqlTemplate
+				if err := TemplatesByService(st, p.(string), nil, func(id string, template credentialtypes.Template) (bool, error) {
+					templates = append(templates, gqlTemplate{id: id, template: template})
+
+					return true, nil
+				}); err != nil {
+					return nil, err
+				}
+
+				return templates, nil
+			}},
+			"template": {args: arg("id", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				id, err := args.String("id")
+				if err != nil {
+					return nil, err
+				}
+
+				return Template(st, p.(string), id)
+			}},
+			"credential": {typ: "Credential", args: []gqlArgDef{{name: "template", typ: "String!"}, {name: "id", typ: "String!"}}, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				template, err := args.String("template")
+				if err != nil {
+					return nil, err
+				}
+
+				id, err := args.String("id")
+				if err != nil {
+					return nil, err
+				}
+
+				credential, isActive, _, err := Credential(st, p.(string), template, id)
+				if err != nil {
+					return nil, err
+				}
+
+				return gqlCredential{credential: *credential, isActive: isActive}, nil
+			}},
+			"holderDID": {args: arg("holder", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				holder, err := args.String("holder")
+				if err != nil {
+					return nil, err
+				}
+
+				return HolderDID(st, p.(string), holder)
+			}},
+		},
+		"Template": {
+			"id":       {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlTemplate).id, nil }},
+			"template": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlTemplate).template, nil }},
+		},
+		"Credential": {
+			"credential": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlCredential).credential, nil
+			}},
+			"active": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlCredential).isActive, nil
+			}},
+			"status": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				now, err := LastBlockTime(st)
+				if err != nil {
+					return nil, err
+				}
+
+				return CredentialLifecycle(p.(gqlCredential).credential, p.(gqlCredential).isActive, now), nil
+			}},
+		},
+		"TimeStampModel": {
+			"contract": {resolve: gqlContract},
+			"service": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				design, _, err := Timestamp(st, p.(string))
+
+				return design, err
+			}},
+			"projects": {typ: "TimeStampProject", list: true, cost: gqlAggregationCost, args: pageArgs, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				offset, err := args.OptionalString("offset")
+				if err != nil {
+					return nil, err
+				}
+
+				reverse, err := args.Bool("reverse")
+				if err != nil {
+					return nil, err
+				}
+
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				return TimestampProjects(st, p.(string), offset, reverse, limit, nil)
+			}},
+			"item": {args: []gqlArgDef{{name: "project", typ: "String!"}, {name: "id", typ: "Int!"}}, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				project, err := args.String("project")
+				if err != nil {
+					return nil, err
+				}
+
+				id, err := args.Int("id", -1)
+				switch {
+				case err != nil:
+					return nil, err
+				case id < 0:
+					return nil, errors.Errorf("argument \"id\" is required")
+				}
+
+				item, _, err := TimestampItem(st, p.(string), project, uint64(id))
+
+				return item, err
+			}},
+		},
+		"TimeStampProject": {
+			"project": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(TimestampProject).Project, nil
+			}},
+			"items": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(TimestampProject).Items, nil
+			}},
+			"lastRequestTimestamp": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(TimestampProject).LastRequestTimestamp, nil
+			}},
+			"lastTimestampIdx": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(TimestampProject).LastTimestampIdx, nil
+			}},
+			"lastHeight": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(TimestampProject).LastHeight, nil
+			}},
+		},
+		"TokenModel": {
+			"contract": {resolve: gqlContract},
+			"design": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return Token(st, p.(string))
+			}},
+			"supply": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return LatestTokenSupply(st, p.(string))
+			}},
+			"balance": {args: arg("address", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				address, err := args.String("address")
+				if err != nil {
+					return nil, err
+				}
+
+				return TokenBalance(st, p.(string), address)
+			}},
+			"holders": {typ: "TokenHolder", list: true, cost: gqlScanCost, resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				holders, err := TokenHolders(st, p.(string), "", false, limit, nil)
+
+				return holders, err
+			}},
+		},
+		"TokenHolder": {
+			"address": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(TokenHolder).Address, nil }},
+			"balance": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(TokenHolder).Balance, nil }},
+			"height":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(TokenHolder).Height, nil }},
+		},
+		"PointModel": {
+			"contract": {resolve: gqlContract},
+			"design": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return Point(st, p.(string))
+			}},
+			"balance": {args: arg("address", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				address, err := args.String("address")
+				if err != nil {
+					return nil, err
+				}
+
+				return PointBalance(st, p.(string), address)
+			}},
+		},
+		"DAOModel": {
+			"contract": {resolve: gqlContract},
+			"design": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return DAOService(st, p.(string))
+			}},
+			"proposal": {typ: "Proposal", args: arg("id", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				id, err := args.String("id")
+				if err != nil {
+					return nil, err
+				}
+
+				proposal, err := DAOProposal(st, p.(string), id)
+				if err != nil {
+					return nil, err
+				}
+
+				return gqlProposal{contract: p.(string), id: id, proposal: *proposal}, nil
+			}},
+			"proposals": {typ: "Proposal", list: true, args: append([]gqlArgDef{{name: "proposer", typ: "String"}, {name: "option", typ: "String"}}, pageArgs...), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				var s [3]string
+				for i, name := range []string{"proposer", "option", "offset"} {
+					v, err := args.OptionalString(name)
+					if err != nil {
+						return nil, err
+					}
+					s[i] = v
+				}
+
+				reverse, err := args.Bool("reverse")
+				if err != nil {
+					return nil, err
+				}
+
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				var proposals []gqlProposal
+				if err := DAOProposals(st, p.(string), s[0], s[1], s[2], reverse, limit, nil,
+					func(proposalID string, proposal daostate.ProposalStateValue) (bool, error) {
+						proposals = append(proposals, gqlProposal{contract: p.(string), id: proposalID, proposal: proposal})
+
+						return true, nil
+					},
+				); err != nil {
+					return nil, err
+				}
+
+				return proposals, nil
+			}},
+			"governance": {typ: "Governance", list: true, cost: gqlAggregationCost, args: arg("address", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				address, err := args.String("address")
+				if err != nil {
+					return nil, err
+				}
+
+				return DAOAccountGovernances(st, p.(string), address, nil)
+			}},
+		},
+		"Governance": {
+			"proposalId":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).ProposalID, nil }},
+			"voted":       {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).Voted, nil }},
+			"voteFor":     {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).VoteFor, nil }},
+			"votingPower": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).VotingPower, nil }},
+			"delegatedTo": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).DelegatedTo, nil }},
+			"delegators":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOAccountGovernance).Delegators, nil }},
+		},
+		"Proposal": {
+			"id": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlProposal).id, nil
+			}},
+			"proposal": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(gqlProposal).proposal, nil
+			}},
+			"status": {cost: gqlLookupCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				design, err := DAOService(st, p.(gqlProposal).contract)
+				if err != nil {
+					return nil, err
+				}
+
+				now, err := LastBlockTime(st)
+				if err != nil {
+					return nil, err
+				}
+
+				return DAOProposalLifecycle(design.Policy(), p.(gqlProposal).proposal, now), nil
+			}},
+			"voters": {typ: "Voter", list: true, cost: gqlScanCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return DAOVoters(st, p.(gqlProposal).contract, p.(gqlProposal).id)
+			}},
+			"votingPowerBox": {cost: gqlLookupCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return DAOVotingPowerBox(st, p.(gqlProposal).contract, p.(gqlProposal).id)
+			}},
+			"tally": {typ: "Tally", cost: gqlAggregationCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return DAOProposalTally(st, p.(gqlProposal).contract, p.(gqlProposal).id)
+			}},
+		},
+		"Voter": {
+			"account": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(daotypes.VoterInfo).Account().String(), nil
+			}},
+			"delegators": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				ds := p.(daotypes.VoterInfo).Delegators()
+
+				delegators := make([]string, len(ds))
+				for i := range ds {
+					delegators[i] = ds[i].String()
+				}
+
+				return delegators, nil
+			}},
+		},
+		"Tally": {
+			"votingPowerTotal": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).VotingPowerTotal, nil }},
+			"votedTotal":       {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).VotedTotal, nil }},
+			"votes":            {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Votes, nil }},
+			"result": {typ: "OptionVotes", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				result := p.(*DAOTally).Result
+
+				options := make([]uint8, 0, len(result))
+				for option := range result {
+					options = append(options, option)
+				}
+
+				sort.Slice(options, func(i, j int) bool { return options[i] < options[j] })
+
+				votes := make([]gqlAmount, len(options))
+				for i, option := range options {
+					votes[i] = gqlAmount{key: strconv.FormatUint(uint64(option), 10), amount: result[option].String()}
+				}
+
+				return votes, nil
+			}},
+			"supply":         {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Supply, nil }},
+			"turnout":        {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Turnout, nil }},
+			"turnoutCount":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).TurnoutCount, nil }},
+			"turnoutReached": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).TurnoutReached, nil }},
+			"quorum":         {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Quorum, nil }},
+			"quorumCount":    {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).QuorumCount, nil }},
+			"quorumReached":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).QuorumReached, nil }},
+			"winner":         {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Winner, nil }},
+			"outcome":        {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Outcome, nil }},
+			"final":          {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*DAOTally).Final, nil }},
+			"voters": {typ: "VoterTally", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(*DAOTally).Voters, nil
+			}},
+		},
+		"OptionVotes": {
+			"option": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlAmount).key, nil }},
+			"votes":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlAmount).amount, nil }},
+		},
+		"VoterTally": {
+			"account":     {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOVoterTally).Account, nil }},
+			"voted":       {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOVoterTally).Voted, nil }},
+			"voteFor":     {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOVoterTally).VoteFor, nil }},
+			"votingPower": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOVoterTally).VotingPower, nil }},
+			"delegators":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(DAOVoterTally).Delegators, nil }},
+		},
+		"STOModel": {
+			"contract": {resolve: gqlContract},
+			"design": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return STOService(st, p.(string))
+			}},
+			"partitions": {typ: "Partition", list: true, cost: gqlAggregationCost, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return STOPartitions(st, p.(string), nil)
+			}},
+			"capTable": {typ: "CapTable", cost: gqlAggregationCost, args: arg("height", "Int"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				height, err := args.Int("height", st.LastBlock().Int64())
+				if err != nil {
+					return nil, err
+				}
+
+				return STOCapTableAt(st, p.(string), mitumbase.Height(height))
+			}},
+			"holderPartitions": {cost: gqlScanCost, args: arg("address", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				address, err := args.String("address")
+				if err != nil {
+					return nil, err
+				}
+
+				return STOHolderPartitions(st, p.(string), address)
+			}},
+			"partitionBalance": {args: arg("partition", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				partition, err := args.String("partition")
+				if err != nil {
+					return nil, err
+				}
+
+				return STOPartitionBalance(st, p.(string), partition)
+			}},
+			"partitionControllers": {args: arg("partition", "String!"), resolve: func(p interface{}, args gqlArgs) (interface{}, error) {
+				partition, err := args.String("partition")
+				if err != nil {
+					return nil, err
+				}
+
+				return STOPartitionControllers(st, p.(string), partition)
+			}},
+		},
+		"Partition": {
+			"partition": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOPartition).Partition, nil }},
+			"balance":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOPartition).Balance, nil }},
+			"holders":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOPartition).Holders, nil }},
+		},
+		"CapTable": {
+			"height":      {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*STOCapTable).Height, nil }},
+			"totalSupply": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(*STOCapTable).TotalSupply, nil }},
+			"partitions": {typ: "PartitionSupply", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				supplies := p.(*STOCapTable).Partitions
+
+				partitions := make([]gqlAmount, 0, len(supplies))
+				for partition := range supplies {
+					partitions = append(partitions, gqlAmount{key: partition, amount: supplies[partition]})
+				}
+
+				sort.Slice(partitions, func(i, j int) bool { return partitions[i].key < partitions[j].key })
+
+				return partitions, nil
+			}},
+			"holders": {typ: "CapTableHolder", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(*STOCapTable).Holders, nil
+			}},
+		},
+		"PartitionSupply": {
+			"partition": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlAmount).key, nil }},
+			"balance":   {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(gqlAmount).amount, nil }},
+		},
+		"CapTableHolder": {
+			"holder":     {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTableHolder).Holder, nil }},
+			"balance":    {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTableHolder).Balance, nil }},
+			"percentage": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTableHolder).Percentage, nil }},
+			"partitions": {typ: "CapTablePartition", list: true, resolve: func(p interface{}, _ gqlArgs) (interface{}, error) {
+				return p.(STOCapTableHolder).Partitions, nil
+			}},
+		},
+		"CapTablePartition": {
+			"partition":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTablePartition).Partition, nil }},
+			"balance":    {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTablePartition).Balance, nil }},
+			"percentage": {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTablePartition).Percentage, nil }},
+			"operators":  {resolve: func(p interface{}, _ gqlArgs) (interface{}, error) { return p.(STOCapTablePartition).Operators, nil }},
+		},
+	}
+}
+
+func gqlContract(p interface{}, _ gqlArgs) (interface{}, error) {
+	return p, nil
+}
+
+func gqlLoadBlock(st *currencydigest.Database, height mitumbase.Height) (interface{}, error) {
+	m, _, confirmed, _, _, err := st.ManifestByHeight(height)
+	switch {
+	case err != nil:
+		return nil, err
+	case m == nil:
+		return nil, mitumutil.ErrNotFound.Errorf("block, %d", height)
+	}
+
+	if len(confirmed) < 1 {
+		confirmed = m.ProposedAt().Format(time.RFC3339Nano)
+	}
+
+	return gqlBlock{height: height, manifest: m, confirmed: confirmed}, nil
+}
@@ -0,0 +1,468 @@
+package digest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+func testGraphQLSchema() gqlSchema {
+	item := func(p interface{}, _ gqlArgs) (interface{}, error) { return p, nil }
+
+	return gqlSchema{
+		"Query": {
+			"hello": {args: []gqlArgDef{{name: "name", typ: "String"}}, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				name, err := args.OptionalString("name")
+
+				return "hello " + name, err
+			}},
+			"heavy": {cost: 1000, resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) { return 0, nil }},
+			"item":  {typ: "Item", resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) { return int64(0), nil }},
+			"items": {typ: "Item", list: true, resolve: func(_ interface{}, args gqlArgs) (interface{}, error) {
+				limit, err := args.Limit()
+				if err != nil {
+					return nil, err
+				}
+
+				items := make([]int64, limit)
+				for i := range items {
+					items[i] = int64(i)
+				}
+
+				return items, nil
+			}},
+			"all": {typ: "Item", list: true, resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) {
+				items := make([]int64, 100)
+				for i := range items {
+					items[i] = int64(i)
+				}
+
+				return items, nil
+			}},
+			"missing": {typ: "Item", resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) {
+				return nil, mitumutil.ErrNotFound.Errorf("item")
+			}},
+			"fail": {resolve: func(_ interface{}, _ gqlArgs) (interface{}, error) {
+				return nil, errors.Errorf("failed")
+			}},
+		},
+		"Item": {
+			"n":        {resolve: item},
+			"sub":      {typ: "Item", resolve: item},
+			"weighted": {cost: 100, resolve: item},
+		},
+	}
+}
+
+func TestParseGraphQL(t *testing.T) {
+	cases := []struct {
+		name          string
+		query         string
+		operationName string
+		keys          []string
+		err           string
+	}{
+		{name: "shorthand", query: `{ a b }`, keys: []string{"a", "b"}},
+		{name: "alias", query: `{ x: a y: a(id: 1) }`, keys: []string{"x", "y"}},
+		{name: "named", query: `query Q { a }`, keys: []string{"a"}},
+		{name: "variables", query: `query Q($l: Int = 3, $s: String!) { a(limit: $l, s: $s) }`, keys: []string{"a"}},
+		{name: "values", query: `{ a(s: "x\"y", i: -1, f: 1.5e1, b: true, n: null, e: ENUM, l: [1, 2], o: {k: "v"}) }`, keys: []string{"a"}},
+		{name: "comment", query: "{\n# comment\n a }", keys: []string{"a"}},
+		{name: "select operation", query: `query A { a } query B { b }`, operationName: "B", keys: []string{"b"}},
+		{name: "empty", query: ` `, err: "empty query"},
+		{name: "empty selection", query: `{ }`, err: "empty selection set"},
+		{name: "unclosed", query: `{ a { b }`, err: "unexpected end of query"},
+		{name: "fragment spread", query: `{ ...F b } fragment F on Query { a }`, keys: []string{"a", "b"}},
+		{name: "fragment before operation", query: `fragment F on Query { a } { ...F }`, keys: []string{"a"}},
+		{name: "nested fragment", query: `{ ...F } fragment F on Query { ...G b } fragment G on Query { a }`, keys: []string{"a", "b"}},
+		{name: "inline fragment", query: `{ ... on Query { a } ... { b } }`, keys: []string{"a", "b"}},
+		{name: "merged fields", query: `{ a { x } b ...F } fragment F on Query { a { y } }`, keys: []string{"a", "b"}},
+		{name: "unknown fragment", query: `{ ...F }`, err: "unknown fragment"},
+		{name: "fragment only", query: `fragment F on Query { a }`, err: "empty query"},
+		{name: "duplicated fragment", query: `{ ...F } fragment F on Query { a } fragment F on Query { b }`, err: "already defined"},
+		{name: "fragment cycle", query: `{ ...F } fragment F on Query { ...G } fragment G on Query { ...F }`, err: "spreads itself"},
+		{name: "fragment without type", query: `{ ...F } fragment F { a }`, err: "expected \"on\""},
+		{name: "conflicting fields", query: `{ x: a x: b }`, err: "conflicts"},
+		{name: "conflicting arguments", query: `{ a(id: 1) ...F } fragment F on Query { a(id: 2) }`, err: "conflicts"},
+		{name: "mutation", query: `mutation { a }`, err: "operation is not supported"},
+		{name: "directive", query: `{ a @skip(if: true) }`, err: "directives are not supported"},
+		{name: "multiple operations", query: `query A { a } query B { b }`, err: "operationName is required"},
+		{name: "unknown operation", query: `query A { a }`, operationName: "B", err: "unknown operation"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			op, err := parseGraphQL(c.query, c.operationName)
+
+			switch {
+			case len(c.err) > 0:
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error %q, but %v", c.err, err)
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			keys := make([]string, len(op.selections))
+			for i := range op.selections {
+				keys[i] = op.selections[i].key()
+			}
+
+			if strings.Join(keys, ",") != strings.Join(c.keys, ",") {
+				t.Errorf("expected fields %v, but %v", c.keys, keys)
+			}
+		})
+	}
+}
+
+func TestGraphQLValidate(t *testing.T) {
+	aliases := func(field string, n int) string {
+		var s []string
+		for i := 0; i < n; i++ {
+			s = append(s, "a"+strings.Repeat("a", i)+": "+field)
+		}
+
+		return "{ " + strings.Join(s, " ") + " }"
+	}
+
+	nested := func(n int) string {
+		return "{ item { " + strings.Repeat("sub { ", n) + "n" + strings.Repeat(" }", n) + " } }"
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		cost      int64
+		err       string
+	}{
+		{name: "leaf", query: `{ hello }`, cost: 1},
+		{name: "weighted leaf", query: `{ heavy }`, cost: 1000},
+		{name: "typename", query: `{ __typename hello }`, cost: 2},
+		{name: "object", query: `{ item { n sub { n } } }`, cost: 4},
+		{name: "default list limit", query: `{ items { n } }`, cost: 1 + defaultGraphQLListCost},
+		{name: "list limit", query: `{ items(limit: 2) { n sub { n } } }`, cost: 7},
+		{name: "list limit over max", query: `{ items(limit: 1000) { n } }`, cost: 1 + maxLimit},
+		{name: "default variable", query: `query ($l: Int = 3) { items(limit: $l) { n } }`, cost: 4},
+		{
+			name: "variable", query: `query ($l: Int = 3) { items(limit: $l) { n } }`,
+			variables: map[string]interface{}{"l": float64(5)}, cost: 6,
+		},
+		{name: "aliased weighted leaves", query: aliases("heavy", 5), cost: 5000},
+		{name: "too expensive", query: aliases("heavy", 6), err: "query is too expensive"},
+		{name: "weighted list", query: `{ items(limit: 49) { weighted } }`, cost: 1 + 49*100},
+		{name: "too expensive list", query: `{ items(limit: 50) { weighted } }`, err: "query is too expensive"},
+		{name: "max aliases", query: aliases("hello", maxGraphQLAliases), cost: int64(maxGraphQLAliases)},
+		{name: "too many aliases", query: aliases("hello", maxGraphQLAliases+1), err: "selected too many times"},
+		{name: "max depth", query: nested(maxGraphQLDepth - 2), cost: int64(maxGraphQLDepth)},
+		{name: "too deep", query: nested(maxGraphQLDepth - 1), err: "query is too deep"},
+		{name: "unknown field", query: `{ nothing }`, err: "unknown field"},
+		{name: "leaf with selection", query: `{ hello { n } }`, err: "must not have a selection"},
+		{name: "object without selection", query: `{ item }`, err: "must have a selection"},
+		{name: "unknown argument", query: `{ hello(nothing: 1) }`, err: "unknown argument"},
+		{name: "list without limit resolver", query: `{ all(limit: 3) { n } }`, cost: 4},
+		{name: "fragment", query: `{ item { ...F } } fragment F on Item { n }`, cost: 2},
+		{name: "fragment on other type", query: `{ item { ...F } } fragment F on Query { hello }`, err: "can not be spread"},
+		{name: "introspection costs nothing", query: `{ __schema { types { name fields { name type { name } } } } }`, cost: 1},
+		{name: "introspection alias", query: `{ __schema { a: types { name } b: types { name } } }`, err: "selected too many times"},
+		{name: "introspection list limit", query: `{ __schema { types(limit: 1) { name } } }`, err: "unknown argument"},
+		{
+			name:  "too deep introspection",
+			query: "{ __type(name: \"Item\") { " + strings.Repeat("ofType { ", maxGraphQLIntrospectionDepth) + "name" + strings.Repeat(" }", maxGraphQLIntrospectionDepth) + " } }",
+			err:   "introspection query is too deep",
+		},
+	}
+
+	schema := testGraphQLSchema()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			op, err := parseGraphQL(c.query, "")
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+
+			variables := map[string]interface{}{}
+			for k := range op.defaults {
+				variables[k] = op.defaults[k]
+			}
+			for k := range c.variables {
+				variables[k] = c.variables[k]
+			}
+
+			cost, err := schema.validate("Query", op.selections, variables, 1)
+
+			switch {
+			case len(c.err) > 0:
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error %q, but %v", c.err, err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case cost != c.cost:
+				t.Errorf("expected cost %d, but %d", c.cost, cost)
+			}
+		})
+	}
+}
+
+func TestExecuteGraphQL(t *testing.T) {
+	cases := []struct {
+		name      string
+		req       GraphQLRequest
+		data      string
+		errorPath string
+		err       string
+	}{
+		{
+			name: "fields in order",
+			req:  GraphQLRequest{Query: `{ b: hello(name: "b") a: hello(name: "a") }`},
+			data: `{"b":"hello b","a":"hello a"}`,
+		},
+		{
+			name: "list",
+			req:  GraphQLRequest{Query: `{ items(limit: 2) { n __typename } }`},
+			data: `{"items":[{"n":0,"__typename":"Item"},{"n":1,"__typename":"Item"}]}`,
+		},
+		{
+			name: "variables",
+			req: GraphQLRequest{
+				Query:     `query ($n: String = "x", $l: Int) { hello(name: $n) items(limit: $l) { n } }`,
+				Variables: map[string]interface{}{"l": float64(1)},
+			},
+			data: `{"hello":"hello x","items":[{"n":0}]}`,
+		},
+		{
+			name: "list is cut by limit",
+			req:  GraphQLRequest{Query: `{ all(limit: 2) { n } x: all { n } }`},
+			data: `{"all":[{"n":0},{"n":1}],"x":[{"n":0},{"n":1},{"n":2},{"n":3},{"n":4},{"n":5},{"n":6},{"n":7},{"n":8},{"n":9}]}`,
+		},
+		{
+			name: "fragments",
+			req:  GraphQLRequest{Query: `{ ...F item { ... on Item { n } } } fragment F on Query { hello(name: "f") }`},
+			data: `{"hello":"hello f","item":{"n":0}}`,
+		},
+		{
+			name: "type",
+			req:  GraphQLRequest{Query: `{ __type(name: "Item") { kind name fields { name type { kind name ofType { name } } } } }`},
+			data: `{"__type":{"kind":"OBJECT","name":"Item","fields":[` +
+				`{"name":"n","type":{"kind":"SCALAR","name":"JSON","ofType":null}},` +
+				`{"name":"sub","type":{"kind":"OBJECT","name":"Item","ofType":null}},` +
+				`{"name":"weighted","type":{"kind":"SCALAR","name":"JSON","ofType":null}}]}}`,
+		},
+		{
+			name: "unknown type",
+			req:  GraphQLRequest{Query: `{ __type(name: "Nothing") { name } }`},
+			data: `{"__type":null}`,
+		},
+		{
+			name: "not found is null",
+			req:  GraphQLRequest{Query: `{ missing { n } }`},
+			data: `{"missing":null}`,
+		},
+		{
+			name:      "resolver error",
+			req:       GraphQLRequest{Query: `{ hello x: fail }`},
+			data:      `{"hello":"hello ","x":null}`,
+			errorPath: `["x"]`,
+		},
+		{
+			name: "invalid",
+			req:  GraphQLRequest{Query: `{ nothing }`},
+			err:  "unknown field",
+		},
+	}
+
+	schema := testGraphQLSchema()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := ExecuteGraphQL(schema, c.req)
+
+			switch {
+			case len(c.err) > 0:
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error %q, but %v", c.err, err)
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(res.Data)
+			if err != nil {
+				t.Fatalf("failed to marshal data: %v", err)
+			}
+
+			if string(b) != c.data {
+				t.Errorf("expected data %s, but %s", c.data, b)
+			}
+
+			switch {
+			case len(c.errorPath) < 1:
+				if len(res.Errors) > 0 {
+					t.Errorf("unexpected errors: %v", res.Errors)
+				}
+			case len(res.Errors) != 1:
+				t.Errorf("expected one error, but %v", res.Errors)
+			default:
+				if p, _ := json.Marshal(res.Errors[0].Path); string(p) != c.errorPath {
+					t.Errorf("expected error path %s, but %s", c.errorPath, p)
+				}
+			}
+		})
+	}
+}
+
+// gqlIntrospectionQuery is the introspection query of GraphiQL.
+const gqlIntrospectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    description
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      isRepeatable
+      locations
+      args(includeDeprecated: true) { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  specifiedByURL
+  fields(includeDeprecated: true) {
+    name
+    description
+    args(includeDeprecated: true) { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields(includeDeprecated: true) { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+  isDeprecated
+  deprecationReason
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } }
+}
+`
+
+func TestGraphQLIntrospection(t *testing.T) {
+	res, err := ExecuteGraphQL(testGraphQLSchema(), GraphQLRequest{Query: gqlIntrospectionQuery})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", res.Errors)
+	}
+
+	b, err := json.Marshal(res.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type typeRef struct {
+		Kind   string   `json:"kind"`
+		Name   *string  `json:"name"`
+		OfType *typeRef `json:"ofType"`
+	}
+
+	var data struct {
+		Schema struct {
+			QueryType struct {
+				Name string `json:"name"`
+			} `json:"queryType"`
+			Types []struct {
+				Kind   string `json:"kind"`
+				Name   string `json:"name"`
+				Fields []struct {
+					Name string `json:"name"`
+					Args []struct {
+						Name string  `json:"name"`
+						Type typeRef `json:"type"`
+					} `json:"args"`
+					Type typeRef `json:"type"`
+				} `json:"fields"`
+			} `json:"types"`
+			Directives []interface{} `json:"directives"`
+		} `json:"__schema"`
+	}
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Schema.QueryType.Name != "Query" {
+		t.Errorf("expected query type, but %q", data.Schema.QueryType.Name)
+	}
+
+	if data.Schema.Directives == nil {
+		t.Error("expected empty directives")
+	}
+
+	kinds := map[string]string{}
+	for _, typ := range data.Schema.Types {
+		kinds[typ.Name] = typ.Kind
+
+		if typ.Kind == "OBJECT" && typ.Fields == nil {
+			t.Errorf("expected fields of %s", typ.Name)
+		}
+
+		if typ.Name != "Query" {
+			continue
+		}
+
+		for _, f := range typ.Fields {
+			if f.Name != "items" {
+				continue
+			}
+
+			if f.Type.Kind != "LIST" || f.Type.OfType == nil || f.Type.OfType.Kind != "NON_NULL" ||
+				f.Type.OfType.OfType == nil || *f.Type.OfType.OfType.Name != "Item" {
+				t.Errorf("expected [Item!], but %s", b)
+			}
+
+			if len(f.Args) != 1 || f.Args[0].Name != "limit" || *f.Args[0].Type.Name != "Int" {
+				t.Errorf("expected limit argument, but %+v", f.Args)
+			}
+		}
+	}
+
+	for name, kind := range map[string]string{
+		"Query": "OBJECT", "Item": "OBJECT", "__Type": "OBJECT",
+		"JSON": "SCALAR", "Int": "SCALAR", "String": "SCALAR", "Boolean": "SCALAR",
+	} {
+		if kinds[name] != kind {
+			t.Errorf("expected %s of %s, but %q", kind, name, kinds[name])
+		}
+	}
+}
@@ -23,35 +23,75 @@ import (
 
 var (
 	HandlerPathNFTOperators                = `/nft/{contract:.*}/account/{address:(?i)` + base.REStringAddressString + `}/operators` // revive:disable-line:line-length-limit
+	HandlerPathAccountPortfolio            = `/account/{address:(?i)` + base.REStringAddressString + `}/portfolio`
+	HandlerPathContracts                   = `/contracts`
+	HandlerPathSearch                      = `/search`
+	HandlerPathGraphQL                     = `/graphql`
+	HandlerPathOpenAPI                     = `/openapi.json`
+	HandlerPathSwaggerUI                   = `/openapi`
 	HandlerPathNFTCollection               = `/nft/{contract:.*}/collection`
 	HandlerPathNFT                         = `/nft/{contract:.*}/{id:.*}`
 	HandlerPathNFTs                        = `/nft/{contract:.*}/nfts`
 	HandlerPathNFTCount                    = `/nft/{contract:.*}/count`
 	HandlerPathDIDService                  = `/did/{contract:.+}/service`
+	HandlerPathDIDTemplates                = `/did/{contract:.+}/templates`
 	HandlerPathDIDCredential               = `/did/{contract:.+}/template/{templateid:.+}/credential/{credentialid:.+}`
 	HandlerPathDIDTemplate                 = `/did/{contract:.+}/template/{templateid:.+}`
 	HandlerPathDIDCredentials              = `/did/{contract:.+}/template/{templateid:.+}/credentials`
+	HandlerPathDIDCredentialsExpiring      = `/did/{contract:.+}/template/{templateid:.+}/credentials/expiring`
+	HandlerPathDIDStatusList               = `/did/{contract:.+}/template/{templateid:.+}/statuslist`
 	HandlerPathDIDHolder                   = `/did/{contract:.+}/holder/{holder:(?i)` + base.REStringAddressString + `}` // revive:disable-line:line-length-limit
 	HandlerPathTimeStampService            = `/timestamp/{contract:.*}/service`
+	HandlerPathTimeStampLookup             = `/timestamp/{contract:.*}/lookup`
+	HandlerPathTimeStampProjects           = `/timestamp/{contract:.*}/projects`
+	HandlerPathTimeStampItems              = `/timestamp/{contract:.*}/project/{project:.+}/items`
 	HandlerPathTimeStampItem               = `/timestamp/{contract:.*}/project/{project:.+}/id/{tid:[0-9]+}`
 	HandlerPathToken                       = `/token/{contract:.*}`
+	HandlerPathTokenAllowance              = `/token/{contract:.*}/account/{owner:(?i)` + base.REStringAddressString + `}/allowance/{spender:(?i)` + base.REStringAddressString + `}`
+	HandlerPathTokenAllowances             = `/token/{contract:.*}/account/{owner:(?i)` + base.REStringAddressString + `}/allowances`
+	HandlerPathTokenHolders                = `/token/{contract:.*}/holders`
+	HandlerPathTokenSupply                 = `/token/{contract:.*}/supply`
 	HandlerPathTokenBalance                = `/token/{contract:.*}/account/{address:(?i)` + base.REStringAddressString + `}` // revive:disable-line:line-length-limit
 	HandlerPathPoint                       = `/point/{contract:.*}`
 	HandlerPathPointBalance                = `/point/{contract:.*}/account/{address:(?i)` + base.REStringAddressString + `}` // revive:disable-line:line-length-limit
+	HandlerPathPointLeaderboard            = `/point/{contract:.*}/leaderboard`
+	HandlerPathPointActivity               = `/point/{contract:.*}/account/{address:(?i)` + base.REStringAddressString + `}/activity`
+	HandlerPathPointAllowance              = `/point/{contract:.*}/account/{owner:(?i)` + base.REStringAddressString + `}/allowance/{spender:(?i)` + base.REStringAddressString + `}`
+	HandlerPathPointAllowances             = `/point/{contract:.*}/account/{owner:(?i)` + base.REStringAddressString + `}/allowances`
 	HandlerPathDAOService                  = `/dao/{contract:\w+}/service`
+	HandlerPathDAOProposals                = `/dao/{contract:\w+}/proposals`
 	HandlerPathDAOProposal                 = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}`
 	HandlerPathDAODelegator                = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}/delegator/{address:(?i)` + base.REStringAddressString + `}`
 	HandlerPathDAOVoters                   = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}/voter`
 	HandlerPathDAOVotingPowerBox           = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}/votingpower` // revive:disable-line:line-length-limit
+	HandlerPathDAOTally                    = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}/tally`
+	HandlerPathDAOTimeline                 = `/dao/{contract:\w+}/proposal/{proposal_id:\w+}/timeline`
+	HandlerPathDAOAccountGovernance        = `/dao/{contract:\w+}/account/{address:(?i)` + base.REStringAddressString + `}/governance`
+	HandlerPathDAODelegations              = `/dao/{contract:\w+}/delegations`
 	HandlerPathSTOService                  = `/sto/{contract:\w+}`
+	HandlerPathSTOCapTable                 = `/sto/{contract:\w+}/captable`
+	HandlerPathSTOPartitions               = `/sto/{contract:\w+}/partitions`
+	HandlerPathSTOCanTransfer              = `/sto/{contract:\w+}/can-transfer`
+	HandlerPathSTOPartitionHolders         = `/sto/{contract:\w+}/partition/{partition:\w+}/holders`
 	HandlerPathSTOHolderPartitions         = `/sto/{contract:\w+}/holder/{address:(?i)` + base.REStringAddressString + `}/partitions`
 	HandlerPathSTOHolderPartitionBalance   = `/sto/{contract:\w+}/holder/{address:(?i)` + base.REStringAddressString + `}/partition/{partition:\w+}/balance`
 	HandlerPathSTOHolderPartitionOperators = `/sto/{contract:\w+}/holder/{address:(?i)` + base.REStringAddressString + `}/partition/{partition:\w+}/operators`
 	HandlerPathSTOPartitionBalance         = `/sto/{contract:\w+}/partition/{partition:\w+}/balance`
-	//HandlerPathSTOPartitionControllers     = `/sto/{contract:\w+}/partition/{partition:\w+}/controllers`
-	HandlerPathSTOOperatorHolders = `/sto/{contract:\w+}/operator/{address:(?i)` + base.REStringAddressString + `}/holders`
+	HandlerPathSTOPartitionControllers     = `/sto/{contract:\w+}/partition/{partition:\w+}/controllers`
+	HandlerPathSTOOperatorHolders          = `/sto/{contract:\w+}/operator/{address:(?i)` + base.REStringAddressString + `}/holders`
 )
 
+// contractModelPaths maps the model names to the paths of their designs.
+var contractModelPaths = map[string]string{
+	"nft":        HandlerPathNFTCollection,
+	"credential": HandlerPathDIDService,
+	"timestamp":  HandlerPathTimeStampService,
+	"token":      HandlerPathToken,
+	"point":      HandlerPathPoint,
+	"dao":        HandlerPathDAOService,
+	"sto":        HandlerPathSTOService,
+}
+
 func init() {
 	if b, err := currencydigest.JSON.Marshal(currencydigest.UnknownProblem); err != nil {
 		panic(err)
@@ -75,6 +115,9 @@ type Handlers struct {
 	itemsLimiter    func(string /* request type */) int64
 	rg              *singleflight.Group
 	expireNotFilled time.Duration
+	gqlSchema       gqlSchema
+	cursorSecret    []byte
+	rateLimiter     *rateLimiter
 }
 
 func NewHandlers(
@@ -104,6 +147,7 @@ func NewHandlers(
 		itemsLimiter:    currencydigest.DefaultItemsLimiter,
 		rg:              &singleflight.Group{},
 		expireNotFilled: time.Second * 3,
+		cursorSecret:    newCursorSecret(),
 	}
 }
 
@@ -116,9 +160,13 @@ func (hd *Handlers) Initialize() error {
 	//)
 	//hd.router.Use(cors)
 
+	hd.gqlSchema = hd.newGraphQLSchema()
+
+	hd.setRateLimitToRoutes()
+
 	hd.setHandlers()
 
-	return nil
+	return hd.checkRateLimitRoutes()
 }
 
 func (hd *Handlers) SetLimiter(f func(string) int64) *Handlers {
@@ -127,6 +175,51 @@ func (hd *Handlers) SetLimiter(f func(string) int64) *Handlers {
 	return hd
 }
 
+// SetRateLimit sets the rate limit of the routes; it should be called before
+// Initialize.
+func (hd *Handlers) SetRateLimit(design RateLimitDesign) error {
+	rl, err := newRateLimiter(design)
+	if err != nil {
+		return errors.WithMessage(err, "rate limit")
+	}
+
+	hd.rateLimiter = rl
+
+	return nil
+}
+
+// setRateLimitToRoutes attaches the rate limit to the routes which are already
+// set, like the routes of the currency handlers.
+func (hd *Handlers) setRateLimitToRoutes() {
+	if hd.rateLimiter == nil {
+		return
+	}
+
+	for prefix, route := range hd.routes {
+		if h := route.GetHandler(); h != nil && hd.rateLimiter.attached(prefix) {
+			_ = route.Handler(hd.rateLimiter.middleware(prefix, h))
+
+			hd.Log().Debug().Str("prefix", prefix).Msg("ratelimit middleware attached")
+		}
+	}
+}
+
+// checkRateLimitRoutes checks the routes of rate limit are known, so the rule
+// of mistyped route is not ignored silently.
+func (hd *Handlers) checkRateLimitRoutes() error {
+	if hd.rateLimiter == nil {
+		return nil
+	}
+
+	for prefix := range hd.rateLimiter.design.Routes {
+		if _, found := hd.routes[prefix]; !found {
+			return errors.Errorf("rate limit; unknown route, %q", prefix)
+		}
+	}
+
+	return nil
+}
+
 func (hd *Handlers) Cache() currencydigest.Cache {
 	return hd.cache
 }
@@ -140,6 +233,18 @@ func (hd *Handlers) Handler() http.Handler {
 }
 
 func (hd *Handlers) setHandlers() {
+	_ = hd.setHandler(HandlerPathAccountPortfolio, hd.handleAccountPortfolio, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathContracts, hd.handleContracts, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSearch, hd.handleSearch, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathGraphQL, hd.handleGraphQL, false).
+		Methods(http.MethodOptions, "GET", http.MethodPost)
+	_ = hd.setHandler(HandlerPathOpenAPI, hd.handleOpenAPI, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSwaggerUI, hd.handleSwaggerUI, false).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathNFTCollection, hd.handleNFTCollection, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathNFTs, hd.handleNFTs, true).
@@ -152,28 +257,58 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDIDService, hd.handleCredentialService, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDIDTemplates, hd.handleTemplates, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDIDCredentials, hd.handleCredentials, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDIDCredential, hd.handleCredential, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDIDCredentialsExpiring, hd.handleCredentialsExpiring, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDIDStatusList, hd.handleCredentialStatusList, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDIDHolder, hd.handleHolderCredential, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDIDTemplate, hd.handleTemplate, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTimeStampLookup, hd.handleTimeStampLookup, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTimeStampProjects, hd.handleTimeStampProjects, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTimeStampItems, hd.handleTimeStampItems, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathTimeStampItem, hd.handleTimeStampItem, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathTimeStampService, hd.handleTimeStamp, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTokenAllowance, hd.handleTokenAllowance, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTokenAllowances, hd.handleTokenAllowances, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTokenHolders, hd.handleTokenHolders, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathTokenSupply, hd.handleTokenSupply, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathTokenBalance, hd.handleTokenBalance, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathToken, hd.handleToken, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathPointLeaderboard, hd.handlePointLeaderboard, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathPointActivity, hd.handlePointActivity, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathPointAllowance, hd.handlePointAllowance, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathPointAllowances, hd.handlePointAllowances, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathPointBalance, hd.handlePointBalance, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathPoint, hd.handlePoint, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDAOService, hd.handleDAOService, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDAOProposals, hd.handleDAOProposals, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDAOProposal, hd.handleDAOProposal, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDAODelegator, hd.handleDAODelegator, true).
@@ -182,8 +317,24 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathDAOVotingPowerBox, hd.handleDAOVotingPowerBox, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDAOTally, hd.handleDAOTally, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDAOTimeline, hd.handleDAOTimeline, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDAOAccountGovernance, hd.handleDAOAccountGovernance, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDAODelegations, hd.handleDAODelegations, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathSTOService, hd.handleSTOService, true).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSTOCapTable, hd.handleSTOCapTable, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSTOPartitions, hd.handleSTOPartitions, true).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSTOCanTransfer, hd.handleSTOCanTransfer, false).
+		Methods(http.MethodOptions, http.MethodPost)
+	_ = hd.setHandler(HandlerPathSTOPartitionHolders, hd.handleSTOPartitionHolders, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathSTOHolderPartitions, hd.handleSTOHolderPartitions, true).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathSTOHolderPartitionBalance, hd.handleSTOHolderPartitionBalance, true).
@@ -192,8 +343,8 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathSTOPartitionBalance, hd.handleSTOPartitionBalance, true).
 		Methods(http.MethodOptions, "GET")
-	//_ = hd.setHandler(HandlerPathSTOPartitionControllers, hd.handleSTOPartitionControllers, true).
-	//	Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathSTOPartitionControllers, hd.handleSTOPartitionControllers, true).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathSTOOperatorHolders, hd.handleSTOOperatorHolders, true).
 		Methods(http.MethodOptions, "GET")
 }
@@ -222,16 +373,11 @@ func (hd *Handlers) setHandler(prefix string, h network.HTTPHandlerFunc, useCach
 		route = hd.router.Name(name)
 	}
 
-	/*
-		if rules, found := hd.rateLimit[prefix]; found {
-			handler = process.NewRateLimitMiddleware(
-				process.NewRateLimit(rules, limiter.Rate{Limit: -1}), // NOTE by default, unlimited
-				hd.rateLimitStore,
-			).Middleware(handler)
+	if hd.rateLimiter != nil && hd.rateLimiter.attached(prefix) {
+		handler = hd.rateLimiter.middleware(prefix, handler)
 
-			hd.Log().Debug().Str("prefix", prefix).Msg("ratelimit middleware attached")
-		}
-	*/
+		hd.Log().Debug().Str("prefix", prefix).Msg("ratelimit middleware attached")
+	}
 
 	route = route.
 		Path(prefix).
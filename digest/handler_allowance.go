@@ -0,0 +1,198 @@
+package digest
+
+import (
+	"net/http"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+)
+
+func (hd *Handlers) handleTokenAllowance(w http.ResponseWriter, r *http.Request) {
+	hd.handleAllowance(w, r, defaultColNameTokenAllowance, HandlerPathTokenAllowance, HandlerPathTokenBalance)
+}
+
+func (hd *Handlers) handleTokenAllowances(w http.ResponseWriter, r *http.Request) {
+	hd.handleAllowances(w, r, defaultColNameTokenAllowance, HandlerPathTokenAllowances, HandlerPathTokenBalance)
+}
+
+func (hd *Handlers) handlePointAllowance(w http.ResponseWriter, r *http.Request) {
+	hd.handleAllowance(w, r, defaultColNamePointAllowance, HandlerPathPointAllowance, HandlerPathPointBalance)
+}
+
+func (hd *Handlers) handlePointAllowances(w http.ResponseWriter, r *http.Request) {
+	hd.handleAllowances(w, r, defaultColNamePointAllowance, HandlerPathPointAllowances, HandlerPathPointBalance)
+}
+
+// handleAllowance serves the allowance granted by an owner for the spender.
+func (hd *Handlers) handleAllowance(w http.ResponseWriter, r *http.Request, col, path, balancePath string) {
+	cachekey := currencydigest.CacheKeyPath(r)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	owner, err, status := parseRequest(w, r, "owner")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	spender, err, status := parseRequest(w, r, "spender")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		return hd.handleAllowanceInGroup(col, path, balancePath, contract, owner, spender)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			currencydigest.HTTP2WriteCache(w, cachekey, time.Millisecond*500)
+		}
+	}
+}
+
+func (hd *Handlers) handleAllowanceInGroup(
+	col, path, balancePath, contract, owner, spender string,
+) (interface{}, error) {
+	allowances, err := AllowancesByOwner(hd.database, col, contract, owner, spender)
+	switch {
+	case err != nil:
+		return nil, mitumutil.ErrNotFound.WithMessage(err,
+			"allowance by contract %s, owner %s, spender %s", contract, owner, spender)
+	case len(allowances) < 1:
+		return nil, mitumutil.ErrNotFound.Errorf(
+			"allowance by contract %s, owner %s, spender %s", contract, owner, spender)
+	}
+
+	h, err := hd.combineURL(path, "contract", contract, "owner", owner, "spender", spender)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(allowances[0], currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(balancePath, "contract", contract, "address", owner)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("balance", currencydigest.NewHalLink(h, nil))
+
+	return hd.encoder.Marshal(hal)
+}
+
+// handleAllowances serves the allowances granted by an owner, ordered by the
+// spender.
+func (hd *Handlers) handleAllowances(w http.ResponseWriter, r *http.Request, col, path, balancePath string) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	owner, err, status := parseRequest(w, r, "owner")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleAllowancesInGroup(col, path, balancePath, contract, owner, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleAllowancesInGroup(
+	col, path, balancePath, contract, owner string,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "allowances")
+
+	all, err := AllowancesByOwner(hd.database, col, contract, owner, "")
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "allowances by contract %s, owner %s", contract, owner)
+	case len(all) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("allowances by contract %s, owner %s", contract, owner)
+	}
+
+	allowances, first, last, filled, err := pageItems(all, func(i Allowance) string { return i.Spender }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	baseSelf, err := hd.combineURL(path, "contract", contract, "owner", owner)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(allowances, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(balancePath, "contract", contract, "address", owner)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("balance", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
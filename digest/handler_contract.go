@@ -0,0 +1,125 @@
+package digest
+
+import (
+	"net/http"
+	"strings"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+func (hd *Handlers) handleContracts(w http.ResponseWriter, r *http.Request) {
+	model := strings.ToLower(currencydigest.ParseStringQuery(r.URL.Query().Get("model")))
+
+	if len(model) > 0 && !IsContractModel(model) {
+		currencydigest.HTTP2ProblemWithError(
+			w, errors.Errorf("invalid model, %q; available: %v", model, contractModelNames), http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path, "model=" + model}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleContractsInGroup(model, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleContractsInGroup(model string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "contracts")
+
+	contracts, err := Contracts(hd.database, model, q.offset, q.scanReverse(), limit, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "contracts, model %q", model)
+	case len(contracts) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("contracts, model %q", model)
+	}
+
+	if q.prev {
+		reverseItems(contracts)
+	}
+
+	vas := make([]currencydigest.Hal, len(contracts))
+	for i := range contracts {
+		h, err := hd.combineURL(currencydigest.HandlerPathAccount, "address", contracts[i].Address)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hal := currencydigest.NewBaseHal(contracts[i], currencydigest.NewHalLink(h, nil))
+
+		for _, m := range contracts[i].Models {
+			if path, found := contractModelPaths[m.Model]; found {
+				h, err := hd.combineURL(path, "contract", contracts[i].Address)
+				if err != nil {
+					return nil, false, err
+				}
+				hal = hal.AddLink(m.Model, currencydigest.NewHalLink(h, nil))
+			}
+		}
+
+		vas[i] = hal
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathContracts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(model) > 0 {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, "model="+model)
+	}
+
+	filled := int64(len(vas)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		contracts[0].Address, contracts[len(contracts)-1].Address,
+		filled,
+		func() (int64, error) {
+			return CountContracts(hd.database, model, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
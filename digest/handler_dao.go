@@ -2,9 +2,12 @@ package digest
 
 import (
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	currencytypes "github.com/ProtoconNet/mitum-currency/v3/types"
 	"github.com/ProtoconNet/mitum-dao/state"
 	"github.com/ProtoconNet/mitum-dao/types"
+	"github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
 	"net/http"
 	"time"
 )
@@ -308,3 +311,709 @@ func (hd *Handlers) buildDAOVotingPowerBoxHal(
 
 	return hal, nil
 }
+
+func (hd *Handlers) handleDAOProposals(w http.ResponseWriter, r *http.Request) {
+	status := currencydigest.ParseStringQuery(r.URL.Query().Get("status"))
+	proposer := currencydigest.ParseStringQuery(r.URL.Query().Get("proposer"))
+	option := currencydigest.ParseStringQuery(r.URL.Query().Get("option"))
+
+	if len(status) > 0 && !IsDAOProposalStatus(status) {
+		currencydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid status, %q", status), http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, st := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, st)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{
+		r.URL.Path,
+		"status=" + status,
+		"proposer=" + proposer,
+		"option=" + option,
+	}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleDAOProposalsInGroup(contract, q, status, proposer, option)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleDAOProposalsInGroup(
+	contract string,
+	q pageQuery,
+	status, proposer, option string,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "dao-proposals")
+
+	design, err := DAOService(hd.database, contract)
+	if err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "dao service, contract %s", contract)
+	}
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// NOTE the status depends on the block time, so the proposals are filtered
+	// after they are loaded and the limit is applied by the callback.
+	queryLimit := limit
+	if len(status) > 0 {
+		queryLimit = 0
+	}
+
+	var ids, statuses []string
+	var proposals []state.ProposalStateValue
+	if err := DAOProposals(
+		hd.database, contract, proposer, option, q.offset, q.scanReverse(), queryLimit, &q.height,
+		func(proposalID string, proposal state.ProposalStateValue) (bool, error) {
+			s := DAOProposalLifecycle(design.Policy(), proposal, now)
+			if len(status) > 0 && s != status {
+				return true, nil
+			}
+
+			ids = append(ids, proposalID)
+			statuses = append(statuses, s)
+			proposals = append(proposals, proposal)
+
+			return int64(len(ids)) < limit, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "proposals, contract %s", contract)
+	} else if len(ids) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("proposals, contract %s", contract)
+	}
+
+	boxes, err := DAOVotingPowerBoxes(hd.database, contract, ids, &q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(ids))
+	for i := range ids {
+		var vpb *types.VotingPowerBox
+		if j, found := boxes[ids[i]]; found {
+			vpb = &j
+		}
+
+		hal, err := hd.buildDAOProposalsItemHal(contract, ids[i], statuses[i], proposals[i], vpb)
+		if err != nil {
+			return nil, false, err
+		}
+
+		vas[i] = hal
+	}
+
+	if q.prev {
+		reverseItems(vas)
+		reverseItems(ids)
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathDAOProposals, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, i := range []struct {
+		name, v string
+	}{{"status", status}, {"proposer", proposer}, {"option", option}} {
+		if len(i.v) > 0 {
+			baseSelf = currencydigest.AddQueryValue(baseSelf, i.name+"="+i.v)
+		}
+	}
+
+	filled := int64(len(vas)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		ids[0], ids[len(ids)-1],
+		filled,
+		func() (int64, error) {
+			if len(status) < 1 {
+				return DAOProposalCount(hd.database, contract, proposer, option, &q.height)
+			}
+
+			var n int64
+
+			err := DAOProposals(hd.database, contract, proposer, option, "", false, 0, &q.height,
+				func(_ string, proposal state.ProposalStateValue) (bool, error) {
+					if DAOProposalLifecycle(design.Policy(), proposal, now) == status {
+						n++
+					}
+
+					return true, nil
+				},
+			)
+
+			return n, err
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) buildDAOProposalsItemHal(
+	contract, proposalID, status string,
+	proposal state.ProposalStateValue,
+	vpb *types.VotingPowerBox,
+) (currencydigest.Hal, error) {
+	var tally *DAOTallySummary
+	if vpb != nil {
+		i := NewDAOTallySummary(*vpb)
+		tally = &i
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOProposal, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(struct {
+		ProposalID string                   `json:"proposal_id"`
+		Status     string                   `json:"status"`
+		Proposal   state.ProposalStateValue `json:"proposal"`
+		Tally      *DAOTallySummary         `json:"tally,omitempty"`
+	}{ProposalID: proposalID, Status: status, Proposal: proposal, Tally: tally}, currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(HandlerPathDAOVotingPowerBox, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("votingpower", currencydigest.NewHalLink(h, nil))
+
+	return hal, nil
+}
+
+func (hd *Handlers) handleDAOTally(w http.ResponseWriter, r *http.Request) {
+	cacheKey := currencydigest.CacheKeyPath(r)
+	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	proposalID, err, status := parseRequest(w, r, "proposal_id")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cacheKey, func() (interface{}, error) {
+		return hd.handleDAOTallyInGroup(contract, proposalID)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			currencydigest.HTTP2WriteCache(w, cacheKey, time.Millisecond*500)
+		}
+	}
+}
+
+func (hd *Handlers) handleDAOTallyInGroup(contract, proposalID string) (interface{}, error) {
+	tally, err := DAOProposalTally(hd.database, contract, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOTally, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(tally, currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(HandlerPathDAOProposal, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("proposal", currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(HandlerPathDAOVoters, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("voters", currencydigest.NewHalLink(h, nil))
+
+	return hd.encoder.Marshal(hal)
+}
+
+func (hd *Handlers) handleDAOAccountGovernance(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	address, err, status := parseRequest(w, r, "address")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleDAOAccountGovernanceInGroup(contract, address, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleDAOAccountGovernanceInGroup(contract, address string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "dao-governance")
+
+	all, err := DAOAccountGovernances(hd.database, contract, address, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "governance, contract %s, account %s", contract, address)
+	case len(all) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("governance, contract %s, account %s", contract, address)
+	}
+
+	governances, first, last, filled, err := pageItems(
+		all, func(i DAOAccountGovernance) string { return i.ProposalID }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(governances))
+	for i := range governances {
+		h, err := hd.combineURL(
+			HandlerPathDAOProposal, "contract", contract, "proposal_id", governances[i].ProposalID)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = currencydigest.NewBaseHal(governances[i], currencydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathDAOAccountGovernance, "contract", contract, "address", address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) handleDAODelegations(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleDAODelegationsInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func daoDelegationKey(d DAODelegation) string {
+	return d.ProposalID + ":" + d.Delegator
+}
+
+// handleDAODelegationsInGroup returns the delegations of the proposals which
+// are not voted yet.
+func (hd *Handlers) handleDAODelegationsInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "dao-delegations")
+
+	design, err := DAOService(hd.database, contract)
+	if err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "dao service, contract %s", contract)
+	}
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var proposalIDs []string
+	if err := DAOProposals(hd.database, contract, "", "", "", false, 0, &q.height,
+		func(proposalID string, proposal state.ProposalStateValue) (bool, error) {
+			switch DAOProposalLifecycle(design.Policy(), proposal, now) {
+			case DAOProposalStatusPreSnapshot, DAOProposalStatusVoting:
+				proposalIDs = append(proposalIDs, proposalID)
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "proposals, contract %s", contract)
+	}
+
+	all, err := DAODelegations(hd.database, contract, proposalIDs, &q.height)
+	if err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "delegations, contract %s", contract)
+	}
+
+	delegations, first, last, filled, err := pageItems(all, daoDelegationKey, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cid := design.Policy().VotingPowerToken().String()
+	if err := hd.setDAODelegationWeights(contract, cid, delegations, q.height); err != nil {
+		return nil, false, err
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathDAODelegations, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if proposalIDs == nil {
+		proposalIDs = []string{}
+	}
+	if delegations == nil {
+		delegations = []DAODelegation{}
+	}
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(struct {
+			VotingPowerToken string          `json:"voting_power_token"`
+			Proposals        []string        `json:"proposals"`
+			Delegations      []DAODelegation `json:"delegations"`
+		}{VotingPowerToken: cid, Proposals: proposalIDs, Delegations: delegations},
+			currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+// setDAODelegationWeights sets the weight of the delegations, the balance of
+// the delegator in the voting power token. For the proposals with voting power
+// box, the balance is the one at the pre-snapshot height, which the voting
+// power of the delegatee was counted by; for the others it is the one at
+// height.
+func (hd *Handlers) setDAODelegationWeights(
+	contract, cid string,
+	delegations []DAODelegation,
+	height base.Height,
+) error {
+	var proposalIDs []string
+	delegators := map[string][]string{}
+
+	for i := range delegations {
+		id := delegations[i].ProposalID
+		if _, found := delegators[id]; !found {
+			proposalIDs = append(proposalIDs, id)
+		}
+		delegators[id] = append(delegators[id], delegations[i].Delegator)
+	}
+
+	snapshots, err := DAOSnapshotHeights(hd.database, contract, proposalIDs)
+	if err != nil {
+		return err
+	}
+
+	// NOTE the balances are loaded once for each snapshot height and once for
+	// the current balances.
+	byHeight := map[base.Height][]string{}
+	var current []string
+
+	for _, id := range proposalIDs {
+		if height, found := snapshots[id]; found {
+			byHeight[height] = append(byHeight[height], delegators[id]...)
+		} else {
+			current = append(current, delegators[id]...)
+		}
+	}
+
+	currentBalances, err := CurrencyBalances(hd.database, current, cid, &height)
+	if err != nil {
+		return err
+	}
+
+	snapshotBalances := map[base.Height]map[string]currencytypes.Amount{}
+	for height := range byHeight {
+		h := height
+
+		if snapshotBalances[height], err = CurrencyBalances(hd.database, byHeight[height], cid, &h); err != nil {
+			return err
+		}
+	}
+
+	for i := range delegations {
+		balances := currentBalances
+		if height, found := snapshots[delegations[i].ProposalID]; found {
+			balances = snapshotBalances[height]
+		}
+
+		if amount, found := balances[delegations[i].Delegator]; found {
+			b := amount.Big()
+			delegations[i].Weight = &b
+		}
+	}
+
+	return nil
+}
+
+func (hd *Handlers) handleDAOTimeline(w http.ResponseWriter, r *http.Request) {
+	cacheKey := currencydigest.CacheKeyPath(r)
+	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	proposalID, err, status := parseRequest(w, r, "proposal_id")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cacheKey, func() (interface{}, error) {
+		return hd.handleDAOTimelineInGroup(contract, proposalID)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			currencydigest.HTTP2WriteCache(w, cacheKey, time.Millisecond*500)
+		}
+	}
+}
+
+type daoTimelinePeriod struct {
+	DAOPeriod
+	StartAt time.Time  `json:"start_at"`
+	EndAt   *time.Time `json:"end_at,omitempty"`
+	Current bool       `json:"current"`
+}
+
+// handleDAOTimelineInGroup computes the periods of the proposal from its start
+// time and the policy of the DAO. The current period is decided by the
+// confirmed time of the last block, not by the clock of the node.
+func (hd *Handlers) handleDAOTimelineInGroup(contract, proposalID string) (interface{}, error) {
+	design, err := DAOService(hd.database, contract)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "dao service, contract %s", contract)
+	}
+
+	proposal, err := DAOProposal(hd.database, contract, proposalID)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "proposal, contract %s, proposalID %s", contract, proposalID)
+	}
+
+	height := hd.database.LastBlock()
+	now, err := BlockTime(hd.database, height)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := DAOPeriods(design.Policy(), proposal.Proposal().StartTime())
+	current := DAOCurrentPeriod(periods, now)
+
+	timeline := make([]daoTimelinePeriod, len(periods))
+	for i := range periods {
+		timeline[i] = daoTimelinePeriod{
+			DAOPeriod: periods[i],
+			StartAt:   time.Unix(int64(periods[i].Start), 0).UTC(),
+			Current:   periods[i].Name == current,
+		}
+
+		if periods[i].End > 0 {
+			t := time.Unix(int64(periods[i].End), 0).UTC()
+			timeline[i].EndAt = &t
+		}
+	}
+
+	h, err := hd.combineURL(HandlerPathDAOTimeline, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(struct {
+		StartTime   uint64              `json:"start_time"`
+		Current     string              `json:"current"`
+		Status      string              `json:"status"`
+		BlockHeight base.Height         `json:"block_height"`
+		BlockTime   time.Time           `json:"block_time"`
+		Periods     []daoTimelinePeriod `json:"periods"`
+	}{
+		StartTime:   proposal.Proposal().StartTime(),
+		Current:     current,
+		Status:      DAOProposalLifecycle(design.Policy(), *proposal, now),
+		BlockHeight: height,
+		BlockTime:   now,
+		Periods:     timeline,
+	}, currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(HandlerPathDAOProposal, "contract", contract, "proposal_id", proposalID)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("proposal", currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(currencydigest.HandlerPathBlockByHeight, "height", height.String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", currencydigest.NewHalLink(h, nil))
+
+	return hd.encoder.Marshal(hal)
+}
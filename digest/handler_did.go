@@ -6,11 +6,14 @@ import (
 	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/pkg/errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ProtoconNet/mitum2/base"
 )
 
+var defaultCredentialExpiringWithin = time.Hour * 24 * 7
+
 func (hd *Handlers) handleCredentialService(w http.ResponseWriter, r *http.Request) {
 	cacheKey := currencydigest.CacheKeyPath(r)
 	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
@@ -63,7 +66,13 @@ func (hd *Handlers) buildCredentialServiceHal(contract string, design types.Desi
 }
 
 func (hd *Handlers) handleCredential(w http.ResponseWriter, r *http.Request) {
-	cacheKey := currencydigest.CacheKeyPath(r)
+	if negotiateVC(w, r) {
+		hd.handleCredentialVC(w, r)
+
+		return
+	}
+
+	cacheKey := mediaCacheKey(currencydigest.CacheKeyPath(r), false)
 	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
 		return
 	}
@@ -99,13 +108,18 @@ func (hd *Handlers) handleCredential(w http.ResponseWriter, r *http.Request) {
 }
 
 func (hd *Handlers) handleCredentialInGroup(contract, templateID, credentialID string) (interface{}, error) {
-	switch credential, isActive, err := Credential(hd.database, contract, templateID, credentialID); {
+	switch credential, isActive, _, err := Credential(hd.database, contract, templateID, credentialID); {
 	case err != nil:
 		return nil, mitumutil.ErrNotFound.WithMessage(err, "credential by contract %s, template %s, id %s", contract, templateID, credentialID)
 	case credential == nil:
 		return nil, mitumutil.ErrNotFound.Errorf("credential by contract %s, template %s, id %s", contract, templateID, credentialID)
 	default:
-		hal, err := hd.buildCredentialHal(contract, *credential, isActive)
+		now, err := LastBlockTime(hd.database)
+		if err != nil {
+			return nil, err
+		}
+
+		hal, err := hd.buildCredentialHal(contract, *credential, isActive, now)
 		if err != nil {
 			return nil, err
 		}
@@ -117,6 +131,7 @@ func (hd *Handlers) buildCredentialHal(
 	contract string,
 	credential types.Credential,
 	isActive bool,
+	now time.Time,
 ) (currencydigest.Hal, error) {
 	h, err := hd.combineURL(
 		HandlerPathDIDCredential,
@@ -132,7 +147,8 @@ func (hd *Handlers) buildCredentialHal(
 		struct {
 			Credential types.Credential `json:"credential"`
 			IsActive   bool             `json:"is_active"`
-		}{Credential: credential, IsActive: isActive},
+			Status     string           `json:"status"`
+		}{Credential: credential, IsActive: isActive, Status: CredentialLifecycle(credential, isActive, now)},
 		currencydigest.NewHalLink(h, nil),
 	)
 
@@ -140,14 +156,32 @@ func (hd *Handlers) buildCredentialHal(
 }
 
 func (hd *Handlers) handleCredentials(w http.ResponseWriter, r *http.Request) {
-	limit := currencydigest.ParseLimitQuery(r.URL.Query().Get("limit"))
-	offset := currencydigest.ParseStringQuery(r.URL.Query().Get("offset"))
-	reverse := currencydigest.ParseBoolQuery(r.URL.Query().Get("reverse"))
+	if negotiateVC(w, r) {
+		hd.handleCredentialsVC(w, r)
 
-	cachekey := currencydigest.CacheKey(
-		r.URL.Path, currencydigest.StringOffsetQuery(offset),
-		currencydigest.StringBoolQuery("reverse", reverse),
-	)
+		return
+	}
+
+	lifecycle, err := parseCredentialLifecycleQuery(r.URL.Query().Get("status"))
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := mediaCacheKey(currencydigest.CacheKey(
+		append([]string{r.URL.Path, stringCredentialLifecycleQuery(lifecycle)}, q.cacheKeys()...)...,
+	), false)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
 
 	contract, err, status := parseRequest(w, r, "contract")
 	if err != nil {
@@ -163,7 +197,7 @@ func (hd *Handlers) handleCredentials(w http.ResponseWriter, r *http.Request) {
 	}
 
 	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
-		i, filled, err := hd.handleCredentialsInGroup(contract, templateID, offset, reverse, limit)
+		i, filled, err := hd.handleCredentialsInGroup(contract, templateID, q, lifecycle)
 
 		return []interface{}{i, filled}, err
 	})
@@ -186,33 +220,27 @@ func (hd *Handlers) handleCredentials(w http.ResponseWriter, r *http.Request) {
 	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
 
 	if !shared {
-		expire := hd.expireNotFilled
-		if len(offset) > 0 && filled {
-			expire = time.Minute
-		}
-
-		currencydigest.HTTP2WriteCache(w, cachekey, expire)
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
 	}
 }
 
 func (hd *Handlers) handleCredentialsInGroup(
 	contract, templateID string,
-	offset string,
-	reverse bool,
-	l int64,
+	q pageQuery,
+	lifecycle string,
 ) ([]byte, bool, error) {
-	var limit int64
-	if l < 0 {
-		limit = hd.itemsLimiter("service-credentials")
-	} else {
-		limit = l
+	limit := hd.pageLimit(q, "service-credentials")
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
 	}
 
 	var vas []currencydigest.Hal
 	if err := CredentialsByServiceAndTemplate(
-		hd.database, contract, templateID, reverse, offset, limit,
+		hd.database, contract, templateID, q.scanReverse(), q.offset, limit, lifecycle, now, &q.height,
 		func(credential types.Credential, isActive bool, st base.State) (bool, error) {
-			hal, err := hd.buildCredentialHal(contract, credential, isActive)
+			hal, err := hd.buildCredentialHal(contract, credential, isActive, now)
 			if err != nil {
 				return false, err
 			}
@@ -226,40 +254,36 @@ func (hd *Handlers) handleCredentialsInGroup(
 		return nil, false, mitumutil.ErrNotFound.Errorf("credentials by contract %s, template %s", contract, templateID)
 	}
 
-	i, err := hd.buildCredentialsHal(contract, templateID, vas, offset, reverse)
+	if q.prev {
+		reverseItems(vas)
+	}
+
+	filled := int64(len(vas)) == limit
+
+	i, err := hd.buildCredentialsHal(contract, templateID, vas, q, filled, lifecycle, now)
 	if err != nil {
 		return nil, false, err
 	}
 
 	b, err := hd.encoder.Marshal(i)
-	return b, int64(len(vas)) == limit, err
+	return b, filled, err
 }
 
 func (hd *Handlers) buildCredentialsHal(
 	contract, templateID string,
 	vas []currencydigest.Hal,
-	offset string,
-	reverse bool,
+	q pageQuery,
+	filled bool,
+	lifecycle string,
+	now time.Time,
 ) (currencydigest.Hal, error) {
-	baseSelf, err := hd.combineURL(
-		HandlerPathDIDCredentials,
-		"contract", contract,
-		"templateid", templateID,
-	)
+	baseSelf, err := hd.credentialsBaseSelf(contract, templateID, lifecycle)
 	if err != nil {
 		return nil, err
 	}
 
-	self := baseSelf
-	if len(offset) > 0 {
-		self = currencydigest.AddQueryValue(baseSelf, currencydigest.StringOffsetQuery(offset))
-	}
-	if reverse {
-		self = currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", reverse))
-	}
-
 	var hal currencydigest.Hal
-	hal = currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(self, nil))
+	hal = currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil))
 
 	h, err := hd.combineURL(HandlerPathDIDService, "contract", contract)
 	if err != nil {
@@ -267,39 +291,79 @@ func (hd *Handlers) buildCredentialsHal(
 	}
 	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
 
-	var nextOffset string
-
-	if len(vas) > 0 {
-		va, ok := vas[len(vas)-1].Interface().(struct {
+	credentialID := func(va currencydigest.Hal) (string, error) {
+		i, ok := va.Interface().(struct {
 			Credential types.Credential `json:"credential"`
 			IsActive   bool             `json:"is_active"`
+			Status     string           `json:"status"`
 		})
 		if !ok {
-			return nil, errors.Errorf("failed to build credentials hal")
+			return "", errors.Errorf("failed to build credentials hal")
 		}
-		nextOffset = va.Credential.ID()
 
+		return i.Credential.ID(), nil
 	}
 
-	if len(nextOffset) > 0 {
-		next := baseSelf
-		next = currencydigest.AddQueryValue(next, currencydigest.StringOffsetQuery(nextOffset))
+	var first, last string
+
+	if len(vas) > 0 {
+		if first, err = credentialID(vas[0]); err != nil {
+			return nil, err
+		}
 
-		if reverse {
-			next = currencydigest.AddQueryValue(next, currencydigest.StringBoolQuery("reverse", reverse))
+		if last, err = credentialID(vas[len(vas)-1]); err != nil {
+			return nil, err
 		}
+	}
+
+	return hd.addPageLinks(hal, baseSelf, q, first, last, filled, func() (int64, error) {
+		return CredentialCountByServiceAndTemplate(hd.database, contract, templateID, lifecycle, now, &q.height)
+	})
+}
 
-		hal = hal.AddLink("next", currencydigest.NewHalLink(next, nil))
+// credentialsBaseSelf is the url of the credentials of the template without
+// the page queries.
+func (hd *Handlers) credentialsBaseSelf(contract, templateID, lifecycle string) (string, error) {
+	baseSelf, err := hd.combineURL(
+		HandlerPathDIDCredentials,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return "", err
 	}
 
-	hal = hal.AddLink("reverse", currencydigest.NewHalLink(currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", !reverse)), nil))
+	if len(lifecycle) > 0 {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, stringCredentialLifecycleQuery(lifecycle))
+	}
 
-	return hal, nil
+	return baseSelf, nil
 }
 
 func (hd *Handlers) handleHolderCredential(w http.ResponseWriter, r *http.Request) {
-	cacheKey := currencydigest.CacheKeyPath(r)
-	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
+	if negotiateVC(w, r) {
+		hd.handleHolderCredentialVC(w, r)
+
+		return
+	}
+
+	lifecycle, err := parseCredentialLifecycleQuery(r.URL.Query().Get("status"))
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := mediaCacheKey(currencydigest.CacheKey(
+		append([]string{r.URL.Path, stringCredentialLifecycleQuery(lifecycle)}, q.cacheKeys()...)...,
+	), false)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
 		return
 	}
 
@@ -315,72 +379,301 @@ func (hd *Handlers) handleHolderCredential(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if v, err, shared := hd.rg.Do(cacheKey, func() (interface{}, error) {
-		return hd.handleHolderCredentialsInGroup(contract, holder)
-	}); err != nil {
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleHolderCredentialsInGroup(contract, holder, lifecycle, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
 		currencydigest.HTTP2HandleError(w, err)
-	} else {
-		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
-		if !shared {
-			currencydigest.HTTP2WriteCache(w, cacheKey, time.Millisecond*500)
-		}
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
 	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+type holderCredential struct {
+	credential types.Credential
+	isActive   bool
 }
 
-func (hd *Handlers) handleHolderCredentialsInGroup(contract, holder string) (interface{}, error) {
+// holderCredentialKey is the offset of the credential in the credentials of
+// holder; the credential id is unique in the template.
+func holderCredentialKey(c holderCredential) string {
+	return c.credential.TemplateID() + ":" + c.credential.ID()
+}
+
+func (hd *Handlers) handleHolderCredentialsInGroup(
+	contract, holder, lifecycle string,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "holder-credentials")
+
 	var did string
 	switch d, err := HolderDID(hd.database, contract, holder); {
 	case err != nil:
-		return nil, mitumutil.ErrNotFound.WithMessage(err, "DID by contract %s, holder %s", contract, holder)
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "DID by contract %s, holder %s", contract, holder)
 	case d == "":
-		return nil, mitumutil.ErrNotFound.Errorf("DID by contract %s, holder %s", contract, holder)
+		return nil, false, mitumutil.ErrNotFound.Errorf("DID by contract %s, holder %s", contract, holder)
 	default:
 		did = d
 	}
 
-	var vas []currencydigest.Hal
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var all []holderCredential
 	if err := CredentialsByServiceHolder(
-		hd.database, contract, holder,
+		hd.database, contract, holder, lifecycle, now, &q.height,
 		func(credential types.Credential, isActive bool, st base.State) (bool, error) {
-			hal, err := hd.buildCredentialHal(contract, credential, isActive)
-			if err != nil {
-				return false, err
-			}
-			vas = append(vas, hal)
+			all = append(all, holderCredential{credential: credential, isActive: isActive})
 
 			return true, nil
 		},
 	); err != nil {
-		return nil, mitumutil.ErrNotFound.WithMessage(err, "credentials by contract %s, holder %s", contract, holder)
-	} else if len(vas) < 1 {
-		return nil, mitumutil.ErrNotFound.Errorf("credentials by contract %s, holder %s", contract, holder)
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "credentials by contract %s, holder %s", contract, holder)
+	} else if len(all) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("credentials by contract %s, holder %s", contract, holder)
 	}
-	hal, err := hd.buildHolderDIDCredentialsHal(contract, holder, did, vas)
+
+	credentials, first, last, filled, err := pageItems(all, holderCredentialKey, q, limit)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	return hd.encoder.Marshal(hal)
-}
 
-func (hd *Handlers) buildHolderDIDCredentialsHal(
-	contract, holder, did string,
-	vas []currencydigest.Hal,
-) (currencydigest.Hal, error) {
-	h, err := hd.combineURL(HandlerPathDIDHolder, "contract", contract, "holder", holder)
+	vas := make([]currencydigest.Hal, len(credentials))
+	for i := range credentials {
+		hal, err := hd.buildCredentialHal(contract, credentials[i].credential, credentials[i].isActive, now)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = hal
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathDIDHolder, "contract", contract, "holder", holder)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	hal := currencydigest.NewBaseHal(
+	if len(lifecycle) > 0 {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, stringCredentialLifecycleQuery(lifecycle))
+	}
+
+	hal, err := hd.addPageLinks(
+		buildHolderDIDCredentialsHal(did, vas, q.selfPageURL(baseSelf)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func buildHolderDIDCredentialsHal(did string, vas []currencydigest.Hal, self string) currencydigest.Hal {
+	return currencydigest.NewBaseHal(
 		struct {
 			DID         string               `json:"did"`
 			Credentials []currencydigest.Hal `json:"credentials"`
 		}{
 			DID:         did,
 			Credentials: vas,
-		}, currencydigest.NewHalLink(h, nil))
+		}, currencydigest.NewHalLink(self, nil))
+}
 
-	return hal, nil
+func parseCredentialLifecycleQuery(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) > 0 && !IsCredentialLifecycle(s) {
+		return "", errors.Errorf("invalid credential status, %q", s)
+	}
+
+	return s, nil
+}
+
+func stringCredentialLifecycleQuery(s string) string {
+	if len(s) < 1 {
+		return ""
+	}
+
+	return "status=" + s
+}
+
+func (hd *Handlers) handleCredentialsExpiring(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	within := defaultCredentialExpiringWithin
+	if s := r.URL.Query().Get("within"); len(s) > 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			currencydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid within, %q", s), http.StatusBadRequest)
+			return
+		}
+		within = d
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path, "within=" + within.String()}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	templateID, err, status := parseRequest(w, r, "templateid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleCredentialsExpiringInGroup(contract, templateID, within, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleCredentialsExpiringInGroup(
+	contract, templateID string,
+	within time.Duration,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "service-credentials")
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var vas []currencydigest.Hal
+	var first, last string
+	if err := CredentialsExpiring(
+		hd.database, contract, templateID, now, within, q.offset, q.scanReverse(), limit, &q.height,
+		func(credential types.Credential, isActive bool, _ base.State) (bool, error) {
+			hal, err := hd.buildCredentialHal(contract, credential, isActive, now)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+
+			if len(first) < 1 {
+				first = CredentialExpiringKey(credential)
+			}
+			last = CredentialExpiringKey(credential)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "expiring credentials by contract %s, template %s", contract, templateID)
+	} else if len(vas) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("expiring credentials by contract %s, template %s", contract, templateID)
+	}
+
+	if q.prev {
+		reverseItems(vas)
+		first, last = last, first
+	}
+
+	h, err := hd.combineURL(
+		HandlerPathDIDCredentialsExpiring,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	baseSelf := currencydigest.AddQueryValue(h, "within="+within.String())
+
+	filled := int64(len(vas)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(
+			struct {
+				Now         time.Time            `json:"now"`
+				Within      string               `json:"within"`
+				Credentials []currencydigest.Hal `json:"credentials"`
+			}{
+				Now:         now,
+				Within:      within.String(),
+				Credentials: vas,
+			},
+			currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil),
+		),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return CredentialExpiringCount(hd.database, contract, templateID, now, within, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err = hd.combineURL(
+		HandlerPathDIDCredentials,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("credentials", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
 }
 
 func (hd *Handlers) handleTemplate(w http.ResponseWriter, r *http.Request) {
@@ -445,3 +738,229 @@ func (hd *Handlers) buildTemplateHal(
 
 	return hal, nil
 }
+
+func (hd *Handlers) handleCredentialStatusList(w http.ResponseWriter, r *http.Request) {
+	if negotiateVC(w, r) {
+		hd.handleCredentialStatusListVC(w, r)
+		return
+	}
+
+	cacheKey := mediaCacheKey(currencydigest.CacheKeyPath(r), false)
+	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	templateID, err, status := parseRequest(w, r, "templateid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cacheKey, func() (interface{}, error) {
+		return hd.handleCredentialStatusListInGroup(contract, templateID)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			currencydigest.HTTP2WriteCache(w, cacheKey, time.Millisecond*500)
+		}
+	}
+}
+
+func (hd *Handlers) handleCredentialStatusListInGroup(contract, templateID string) (interface{}, error) {
+	switch list, err := CredentialStatusListByTemplate(hd.database, contract, templateID); {
+	case err != nil:
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "status list by contract %s, template %s", contract, templateID)
+	case list == nil:
+		return nil, mitumutil.ErrNotFound.Errorf("status list by contract %s, template %s", contract, templateID)
+	default:
+		hal, err := hd.buildCredentialStatusListHal(contract, templateID, *list)
+		if err != nil {
+			return nil, err
+		}
+		return hd.encoder.Marshal(hal)
+	}
+}
+
+func (hd *Handlers) buildCredentialStatusListHal(
+	contract, templateID string,
+	list CredentialStatusList,
+) (currencydigest.Hal, error) {
+	h, err := hd.combineURL(
+		HandlerPathDIDStatusList,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(list, currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(
+		HandlerPathDIDTemplate,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("template", currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(currencydigest.HandlerPathBlockByHeight, "height", base.Height(list.Height).String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", currencydigest.NewHalLink(h, nil))
+
+	return hal, nil
+}
+
+func (hd *Handlers) handleTemplates(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTemplatesInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+type serviceTemplate struct {
+	id       string
+	template types.Template
+}
+
+func (hd *Handlers) handleTemplatesInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "templates")
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var all []serviceTemplate
+	if err := TemplatesByService(
+		hd.database, contract, &q.height,
+		func(templateID string, template types.Template) (bool, error) {
+			all = append(all, serviceTemplate{id: templateID, template: template})
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "templates by contract %s", contract)
+	} else if len(all) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("templates by contract %s", contract)
+	}
+
+	templates, first, last, filled, err := pageItems(all, func(i serviceTemplate) string { return i.id }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(templates))
+	for i := range templates {
+		stats, err := CredentialTemplateStats(hd.database, contract, templates[i].id, now)
+		if err != nil {
+			return nil, false, err
+		}
+
+		h, err := hd.combineURL(
+			HandlerPathDIDTemplate,
+			"contract", contract,
+			"templateid", templates[i].id,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hal := currencydigest.NewBaseHal(
+			struct {
+				Template   types.Template               `json:"template"`
+				Statistics CredentialTemplateStatistics `json:"statistics"`
+			}{Template: templates[i].template, Statistics: stats},
+			currencydigest.NewHalLink(h, nil),
+		)
+
+		h, err = hd.combineURL(
+			HandlerPathDIDCredentials,
+			"contract", contract,
+			"templateid", templates[i].id,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = hal.AddLink("credentials", currencydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathDIDTemplates, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathDIDService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
@@ -0,0 +1,516 @@
+package digest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtoconNet/mitum-credential/types"
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+)
+
+const (
+	VCMimeType           = "application/vc+ld+json"
+	VCContextV2          = "https://www.w3.org/ns/credentials/v2"
+	VCTypeCredential     = "VerifiableCredential"
+	VCTypePresentation   = "VerifiablePresentation"
+	VCAttestationType    = "MitumDigestStateAttestation"
+	VCDIDMethodPrefix    = "did:mitum:"
+	halMimeType          = "application/hal+json"
+	vcTimestampLayout    = time.RFC3339
+	vcCredentialIDPrefix = "urn:mitum:credential:"
+	VCContextStatusList  = "https://w3id.org/vc/status-list/2021/v1"
+	VCTypeStatusList     = "StatusList2021Credential"
+	vcStatusListSubject  = "StatusList2021"
+	vcStatusListEntry    = "StatusList2021Entry"
+	vcStatusPurpose      = "revocation"
+)
+
+type VerifiableCredential struct {
+	Context           []string            `json:"@context"`
+	ID                string              `json:"id"`
+	Type              []string            `json:"type"`
+	Issuer            VCIssuer            `json:"issuer"`
+	ValidFrom         string              `json:"validFrom,omitempty"`
+	ValidUntil        string              `json:"validUntil,omitempty"`
+	CredentialSubject VCCredentialSubject `json:"credentialSubject"`
+	CredentialStatus  *VCCredentialStatus `json:"credentialStatus,omitempty"`
+	Attestation       *VCAttestation      `json:"attestation,omitempty"`
+}
+
+type VCIssuer struct {
+	ID string `json:"id"`
+}
+
+type VCCredentialSubject struct {
+	ID         string `json:"id"`
+	Holder     string `json:"holder"`
+	TemplateID string `json:"templateId"`
+	Value      string `json:"value"`
+}
+
+type VCCredentialStatus struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+type VCStatusListCredential struct {
+	Context           []string            `json:"@context"`
+	ID                string              `json:"id"`
+	Type              []string            `json:"type"`
+	Issuer            VCIssuer            `json:"issuer"`
+	CredentialSubject VCStatusListSubject `json:"credentialSubject"`
+}
+
+type VCStatusListSubject struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	StatusPurpose string `json:"statusPurpose"`
+	EncodedList   string `json:"encodedList"`
+}
+
+// VCAttestation is not a proof; nothing is signed. It tells where the
+// credential state was digested from, the block and the facts of the
+// operations which changed the state. The verifier should check the facts in
+// the block by itself, or trust the digest node.
+type VCAttestation struct {
+	Type        string   `json:"type"`
+	FactHashes  []string `json:"factHashes"`
+	BlockHeight string   `json:"blockHeight"`
+	Block       string   `json:"block"`
+}
+
+type VerifiablePresentation struct {
+	Context              []string               `json:"@context"`
+	Type                 []string               `json:"type"`
+	Holder               string                 `json:"holder"`
+	VerifiableCredential []VerifiableCredential `json:"verifiableCredential"`
+}
+
+// negotiateVC reports whether the client asked for the W3C Verifiable
+// Credential representation instead of the default HAL document. The response
+// varies by Accept, so the caches should keep them apart.
+func negotiateVC(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Add("Vary", "Accept")
+
+	return acceptsVC(r)
+}
+
+// mediaCacheKey adds the media type of the response to the cache key.
+func mediaCacheKey(key string, vc bool) string {
+	if vc {
+		return key + "#" + VCMimeType
+	}
+
+	return key + "#" + halMimeType
+}
+
+func acceptsVC(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		for _, t := range strings.Split(v, ",") {
+			if i := strings.Index(t, ";"); i >= 0 {
+				t = t[:i]
+			}
+
+			if strings.EqualFold(strings.TrimSpace(t), VCMimeType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func writeVCBytes(w http.ResponseWriter, b []byte, status int) {
+	w.Header().Set("Content-Type", VCMimeType)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func vcTime(t uint64) string {
+	if t < 1 {
+		return ""
+	}
+
+	return time.Unix(int64(t), 0).UTC().Format(vcTimestampLayout)
+}
+
+func vcDID(address string) string {
+	if strings.HasPrefix(address, "did:") {
+		return address
+	}
+
+	return VCDIDMethodPrefix + address
+}
+
+func (hd *Handlers) buildVerifiableCredential(
+	contract string,
+	credential types.Credential,
+	did string,
+	st base.State,
+	indexes map[string]uint64,
+) (VerifiableCredential, error) {
+	subject := did
+	if len(subject) < 1 {
+		subject = vcDID(credential.Holder().String())
+	}
+
+	vc := VerifiableCredential{
+		Context:    []string{VCContextV2},
+		ID:         vcCredentialIDPrefix + contract + ":" + credential.TemplateID() + ":" + credential.ID(),
+		Type:       []string{VCTypeCredential},
+		Issuer:     VCIssuer{ID: vcDID(contract)},
+		ValidFrom:  vcTime(credential.ValidFrom()),
+		ValidUntil: vcTime(credential.ValidUntil()),
+		CredentialSubject: VCCredentialSubject{
+			ID:         subject,
+			Holder:     credential.Holder().String(),
+			TemplateID: credential.TemplateID(),
+			Value:      credential.Value(),
+		},
+	}
+
+	var index uint64
+	var found bool
+	if indexes != nil {
+		index, found = indexes[credential.ID()]
+	} else if i, err := CredentialStatusIndex(hd.database, contract, credential.TemplateID(), credential.ID()); err == nil {
+		index, found = i, true
+	}
+
+	if found {
+		list, err := hd.combineURL(
+			HandlerPathDIDStatusList,
+			"contract", contract,
+			"templateid", credential.TemplateID(),
+		)
+		if err != nil {
+			return VerifiableCredential{}, err
+		}
+
+		i := strconv.FormatUint(index, 10)
+		vc.Context = append(vc.Context, VCContextStatusList)
+		vc.CredentialStatus = &VCCredentialStatus{
+			ID:                   list + "#" + i,
+			Type:                 vcStatusListEntry,
+			StatusPurpose:        vcStatusPurpose,
+			StatusListIndex:      i,
+			StatusListCredential: list,
+		}
+	}
+
+	if st != nil {
+		block, err := hd.combineURL(currencydigest.HandlerPathBlockByHeight, "height", st.Height().String())
+		if err != nil {
+			return VerifiableCredential{}, err
+		}
+
+		facts := make([]string, len(st.Operations()))
+		for i := range st.Operations() {
+			facts[i] = st.Operations()[i].String()
+		}
+
+		vc.Attestation = &VCAttestation{
+			Type:        VCAttestationType,
+			FactHashes:  facts,
+			BlockHeight: st.Height().String(),
+			Block:       block,
+		}
+	}
+
+	return vc, nil
+}
+
+func (hd *Handlers) handleCredentialVC(w http.ResponseWriter, r *http.Request) {
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	templateID, err, status := parseRequest(w, r, "templateid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	credentialID, err, status := parseRequest(w, r, "credentialid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	if v, err, _ := hd.rg.Do(mediaCacheKey(currencydigest.CacheKeyPath(r), true), func() (interface{}, error) {
+		return hd.handleCredentialVCInGroup(contract, templateID, credentialID)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		writeVCBytes(w, v.([]byte), http.StatusOK)
+	}
+}
+
+func (hd *Handlers) handleCredentialVCInGroup(contract, templateID, credentialID string) ([]byte, error) {
+	switch credential, _, st, err := Credential(hd.database, contract, templateID, credentialID); {
+	case err != nil:
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "credential by contract %s, template %s, id %s", contract, templateID, credentialID)
+	case credential == nil:
+		return nil, mitumutil.ErrNotFound.Errorf("credential by contract %s, template %s, id %s", contract, templateID, credentialID)
+	default:
+		did, _ := HolderDID(hd.database, contract, credential.Holder().String())
+
+		vc, err := hd.buildVerifiableCredential(contract, *credential, did, st, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return currencydigest.JSON.Marshal(vc)
+	}
+}
+
+func (hd *Handlers) handleCredentialsVC(w http.ResponseWriter, r *http.Request) {
+	lifecycle, err := parseCredentialLifecycleQuery(r.URL.Query().Get("status"))
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	templateID, err, status := parseRequest(w, r, "templateid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	cachekey := mediaCacheKey(currencydigest.CacheKey(
+		append([]string{r.URL.Path, stringCredentialLifecycleQuery(lifecycle)}, q.cacheKeys()...)...,
+	), true)
+
+	v, err, _ := hd.rg.Do(cachekey, func() (interface{}, error) {
+		b, links, err := hd.handleCredentialsVCInGroup(contract, templateID, q, lifecycle)
+
+		return []interface{}{b, links}, err
+	})
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	l := v.([]interface{})
+	w.Header().Set("Link", linkHeader(l[1].([]pageLink)))
+	writeVCBytes(w, l[0].([]byte), http.StatusOK)
+}
+
+// handleCredentialsVCInGroup returns the page of the verifiable credentials;
+// the body is the bare array of the credentials, so the page links are
+// returned for the Link header.
+func (hd *Handlers) handleCredentialsVCInGroup(
+	contract, templateID string,
+	q pageQuery,
+	lifecycle string,
+) ([]byte, []pageLink, error) {
+	limit := hd.pageLimit(q, "service-credentials")
+
+	now, err := BlockTime(hd.database, q.height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dids := map[string]string{}
+
+	indexes, err := CredentialStatusIndexes(hd.database, contract, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var vcs []VerifiableCredential
+	var ids []string
+	if err := CredentialsByServiceAndTemplate(
+		hd.database, contract, templateID, q.scanReverse(), q.offset, limit, lifecycle, now, &q.height,
+		func(credential types.Credential, _ bool, st base.State) (bool, error) {
+			holder := credential.Holder().String()
+
+			did, found := dids[holder]
+			if !found {
+				did, _ = HolderDID(hd.database, contract, holder)
+				dids[holder] = did
+			}
+
+			vc, err := hd.buildVerifiableCredential(contract, credential, did, st, indexes)
+			if err != nil {
+				return false, err
+			}
+			vcs = append(vcs, vc)
+			ids = append(ids, credential.ID())
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, nil, mitumutil.ErrNotFound.WithMessage(err, "credentials by contract %s, template %s", contract, templateID)
+	} else if len(vcs) < 1 {
+		return nil, nil, mitumutil.ErrNotFound.Errorf("credentials by contract %s, template %s", contract, templateID)
+	}
+
+	if q.prev {
+		reverseItems(vcs)
+		reverseItems(ids)
+	}
+
+	baseSelf, err := hd.credentialsBaseSelf(contract, templateID, lifecycle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	links, err := hd.pageLinks(baseSelf, q, ids[0], ids[len(ids)-1], int64(len(vcs)) == limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, err := currencydigest.JSON.Marshal(vcs)
+
+	return b, links, err
+}
+
+func (hd *Handlers) handleHolderCredentialVC(w http.ResponseWriter, r *http.Request) {
+	lifecycle, err := parseCredentialLifecycleQuery(r.URL.Query().Get("status"))
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	holder, err, status := parseRequest(w, r, "holder")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	cachekey := mediaCacheKey(currencydigest.CacheKey(r.URL.Path, stringCredentialLifecycleQuery(lifecycle)), true)
+
+	if v, err, _ := hd.rg.Do(cachekey, func() (interface{}, error) {
+		return hd.handleHolderCredentialVCInGroup(contract, holder, lifecycle)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		writeVCBytes(w, v.([]byte), http.StatusOK)
+	}
+}
+
+func (hd *Handlers) handleHolderCredentialVCInGroup(contract, holder, lifecycle string) ([]byte, error) {
+	did, err := HolderDID(hd.database, contract, holder)
+	switch {
+	case err != nil:
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "DID by contract %s, holder %s", contract, holder)
+	case did == "":
+		return nil, mitumutil.ErrNotFound.Errorf("DID by contract %s, holder %s", contract, holder)
+	}
+
+	now, err := LastBlockTime(hd.database)
+	if err != nil {
+		return nil, err
+	}
+
+	var vcs []VerifiableCredential
+	if err := CredentialsByServiceHolder(
+		hd.database, contract, holder, lifecycle, now, nil,
+		func(credential types.Credential, _ bool, st base.State) (bool, error) {
+			vc, err := hd.buildVerifiableCredential(contract, credential, did, st, nil)
+			if err != nil {
+				return false, err
+			}
+			vcs = append(vcs, vc)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "credentials by contract %s, holder %s", contract, holder)
+	} else if len(vcs) < 1 {
+		return nil, mitumutil.ErrNotFound.Errorf("credentials by contract %s, holder %s", contract, holder)
+	}
+
+	return currencydigest.JSON.Marshal(VerifiablePresentation{
+		Context:              []string{VCContextV2},
+		Type:                 []string{VCTypePresentation},
+		Holder:               vcDID(did),
+		VerifiableCredential: vcs,
+	})
+}
+
+func (hd *Handlers) handleCredentialStatusListVC(w http.ResponseWriter, r *http.Request) {
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	templateID, err, status := parseRequest(w, r, "templateid")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+		return
+	}
+
+	if v, err, _ := hd.rg.Do(mediaCacheKey(currencydigest.CacheKeyPath(r), true), func() (interface{}, error) {
+		return hd.handleCredentialStatusListVCInGroup(contract, templateID)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		writeVCBytes(w, v.([]byte), http.StatusOK)
+	}
+}
+
+func (hd *Handlers) handleCredentialStatusListVCInGroup(contract, templateID string) ([]byte, error) {
+	list, err := CredentialStatusListByTemplate(hd.database, contract, templateID)
+	switch {
+	case err != nil:
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "status list by contract %s, template %s", contract, templateID)
+	case list == nil:
+		return nil, mitumutil.ErrNotFound.Errorf("status list by contract %s, template %s", contract, templateID)
+	}
+
+	self, err := hd.combineURL(
+		HandlerPathDIDStatusList,
+		"contract", contract,
+		"templateid", templateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return currencydigest.JSON.Marshal(VCStatusListCredential{
+		Context: []string{VCContextV2, VCContextStatusList},
+		ID:      self,
+		Type:    []string{VCTypeCredential, VCTypeStatusList},
+		Issuer:  VCIssuer{ID: vcDID(contract)},
+		CredentialSubject: VCStatusListSubject{
+			ID:            self + "#list",
+			Type:          vcStatusListSubject,
+			StatusPurpose: vcStatusPurpose,
+			EncodedList:   list.EncodedList,
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package digest
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateVC(t *testing.T) {
+	cases := []struct {
+		name   string
+		accept []string
+		vc     bool
+	}{
+		{name: "empty"},
+		{name: "json", accept: []string{"application/json"}},
+		{name: "vc", accept: []string{VCMimeType}, vc: true},
+		{name: "vc with params", accept: []string{"application/json, application/vc+ld+json; q=0.9"}, vc: true},
+		{name: "case insensitive", accept: []string{"Application/VC+LD+JSON"}, vc: true},
+		{name: "multiple headers", accept: []string{"text/html", VCMimeType}, vc: true},
+		{name: "similar", accept: []string{"application/vc+json"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for _, v := range c.accept {
+				r.Header.Add("Accept", v)
+			}
+
+			w := httptest.NewRecorder()
+
+			if vc := negotiateVC(w, r); vc != c.vc {
+				t.Errorf("expected %v, but %v", c.vc, vc)
+			}
+
+			if v := w.Header().Get("Vary"); v != "Accept" {
+				t.Errorf("expected Vary Accept, but %q", v)
+			}
+		})
+	}
+}
+
+func TestMediaCacheKey(t *testing.T) {
+	if mediaCacheKey("/a", true) == mediaCacheKey("/a", false) {
+		t.Error("same cache key for different media types")
+	}
+}
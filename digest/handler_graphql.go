@@ -0,0 +1,59 @@
+package digest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+func (hd *Handlers) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+
+	switch r.Method {
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLQuerySize)).Decode(&req); err != nil {
+			currencydigest.HTTP2ProblemWithError(w, errors.Wrap(err, "invalid request body"), http.StatusBadRequest)
+
+			return
+		}
+	default:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+
+		if s := r.URL.Query().Get("variables"); len(s) > 0 {
+			if err := json.Unmarshal([]byte(s), &req.Variables); err != nil {
+				currencydigest.HTTP2ProblemWithError(w, errors.Wrap(err, "invalid variables"), http.StatusBadRequest)
+
+				return
+			}
+		}
+	}
+
+	if int64(len(req.Query)) > maxGraphQLQuerySize {
+		currencydigest.HTTP2ProblemWithError(w, errors.Errorf("too long query"), http.StatusBadRequest)
+
+		return
+	}
+
+	status := http.StatusOK
+
+	res, err := ExecuteGraphQL(hd.gqlSchema, req)
+	if err != nil {
+		status = http.StatusBadRequest
+		res = &GraphQLResponse{Errors: []gqlError{{Message: err.Error()}}}
+	}
+
+	b, err := mitumutil.MarshalJSON(res)
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
@@ -115,15 +115,19 @@ func (hd *Handlers) buildNFTCollectionHal(contract string, design types.Design)
 }
 
 func (hd *Handlers) handleNFTs(w http.ResponseWriter, r *http.Request) {
-	limit := currencydigest.ParseLimitQuery(r.URL.Query().Get("limit"))
-	offset := currencydigest.ParseStringQuery(r.URL.Query().Get("offset"))
-	reverse := currencydigest.ParseBoolQuery(r.URL.Query().Get("reverse"))
 	facthash := currencydigest.ParseStringQuery(r.URL.Query().Get("facthash"))
 
-	cachekey := currencydigest.CacheKey(
-		r.URL.Path, currencydigest.StringOffsetQuery(offset),
-		currencydigest.StringBoolQuery("reverse", reverse),
-	)
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path, "facthash=" + facthash}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
 
 	contract, err, status := parseRequest(w, r, "contract")
 	if err != nil {
@@ -133,7 +137,7 @@ func (hd *Handlers) handleNFTs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
-		i, filled, err := hd.handleNFTsInGroup(contract, facthash, offset, reverse, limit)
+		i, filled, err := hd.handleNFTsInGroup(contract, facthash, q)
 
 		return []interface{}{i, filled}, err
 	})
@@ -156,30 +160,19 @@ func (hd *Handlers) handleNFTs(w http.ResponseWriter, r *http.Request) {
 	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
 
 	if !shared {
-		expire := hd.expireNotFilled
-		if len(offset) > 0 && filled {
-			expire = time.Minute
-		}
-
-		currencydigest.HTTP2WriteCache(w, cachekey, expire)
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
 	}
 }
 
 func (hd *Handlers) handleNFTsInGroup(
-	contract, facthash, offset string,
-	reverse bool,
-	l int64,
+	contract, facthash string,
+	q pageQuery,
 ) ([]byte, bool, error) {
-	var limit int64
-	if l < 0 {
-		limit = hd.itemsLimiter("collection-nfts")
-	} else {
-		limit = l
-	}
+	limit := hd.pageLimit(q, "collection-nfts")
 
 	var vas []currencydigest.Hal
 	if err := NFTsByCollection(
-		hd.database, contract, facthash, offset, reverse, limit,
+		hd.database, contract, facthash, q.offset, q.scanReverse(), limit, &q.height,
 		func(nft types.NFT, st base.State) (bool, error) {
 			hal, err := hd.buildNFTHal(contract, nft)
 			if err != nil {
@@ -195,36 +188,38 @@ func (hd *Handlers) handleNFTsInGroup(
 		return nil, false, mitumutil.ErrNotFound.Errorf("nft tokens by contract, %s", contract)
 	}
 
-	i, err := hd.buildNFTsHal(contract, vas, offset, reverse)
+	if q.prev {
+		reverseItems(vas)
+	}
+
+	filled := int64(len(vas)) == limit
+
+	i, err := hd.buildNFTsHal(contract, facthash, vas, q, filled)
 	if err != nil {
 		return nil, false, err
 	}
 
 	b, err := hd.encoder.Marshal(i)
-	return b, int64(len(vas)) == limit, err
+	return b, filled, err
 }
 
 func (hd *Handlers) buildNFTsHal(
-	contract string,
+	contract, facthash string,
 	vas []currencydigest.Hal,
-	offset string,
-	reverse bool,
+	q pageQuery,
+	filled bool,
 ) (currencydigest.Hal, error) {
 	baseSelf, err := hd.combineURL(HandlerPathNFTs, "contract", contract)
 	if err != nil {
 		return nil, err
 	}
 
-	self := baseSelf
-	if len(offset) > 0 {
-		self = currencydigest.AddQueryValue(baseSelf, currencydigest.StringOffsetQuery(offset))
-	}
-	if reverse {
-		self = currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", reverse))
+	if len(facthash) > 0 {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, "facthash="+facthash)
 	}
 
 	var hal currencydigest.Hal
-	hal = currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(self, nil))
+	hal = currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil))
 
 	h, err := hd.combineURL(HandlerPathNFTCollection, "contract", contract)
 	if err != nil {
@@ -232,33 +227,15 @@ func (hd *Handlers) buildNFTsHal(
 	}
 	hal = hal.AddLink("collection", currencydigest.NewHalLink(h, nil))
 
-	var nextoffset string
-
+	var first, last string
 	if len(vas) > 0 {
-		va := vas[len(vas)-1].Interface().(types.NFT)
-		nextoffset = strconv.FormatUint(va.ID(), 10)
-	}
-
-	if len(nextoffset) > 0 {
-		next := baseSelf
-		next = currencydigest.AddQueryValue(next, currencydigest.StringOffsetQuery(nextoffset))
-
-		if reverse {
-			next = currencydigest.AddQueryValue(next, currencydigest.StringBoolQuery("reverse", reverse))
-		}
-
-		hal = hal.AddLink("next", currencydigest.NewHalLink(next, nil))
+		first = strconv.FormatUint(vas[0].Interface().(types.NFT).ID(), 10)
+		last = strconv.FormatUint(vas[len(vas)-1].Interface().(types.NFT).ID(), 10)
 	}
 
-	hal = hal.AddLink(
-		"reverse",
-		currencydigest.NewHalLink(
-			currencydigest.AddQueryValue(baseSelf, currencydigest.StringBoolQuery("reverse", !reverse)),
-			nil,
-		),
-	)
-
-	return hal, nil
+	return hd.addPageLinks(hal, baseSelf, q, first, last, filled, func() (int64, error) {
+		return NFTCountByCollection(hd.database, contract, facthash, &q.height)
+	})
 }
 
 func (hd *Handlers) handleNFTCount(w http.ResponseWriter, r *http.Request) {
@@ -310,7 +287,7 @@ func (hd *Handlers) handleNFTCountInGroup(
 	contract string,
 ) ([]byte, error) {
 	count, err := NFTCountByCollection(
-		hd.database, contract,
+		hd.database, contract, "", nil,
 	)
 	if err != nil {
 		return nil, mitumutil.ErrNotFound.WithMessage(err, "nft count by contract, %s", contract)
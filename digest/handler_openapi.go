@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"embed"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+)
+
+// The page of Swagger UI is embedded; it loads the assets of the pinned
+// version of swagger-ui-dist from the CDN and the document from
+// HandlerPathOpenAPI.
+//
+//go:embed swaggerui
+var swaggerUIFiles embed.FS
+
+func (hd *Handlers) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	cachekey := currencydigest.CacheKeyPath(r)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		doc, err := hd.openAPIDocument()
+		if err != nil {
+			return nil, err
+		}
+
+		return mitumutil.MarshalJSON(doc)
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(v.([]byte))
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, time.Hour)
+	}
+}
+
+func (hd *Handlers) handleSwaggerUI(w http.ResponseWriter, _ *http.Request) {
+	writeSwaggerUIFile(w, "index.html")
+}
+
+func writeSwaggerUIFile(w http.ResponseWriter, file string) {
+	b, err := swaggerUIFiles.ReadFile(path.Join("swaggerui", file))
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, mitumutil.ErrNotFound.Errorf("file, %s", file), http.StatusNotFound)
+
+		return
+	}
+
+	if t := mime.TypeByExtension(path.Ext(file)); len(t) > 0 {
+		w.Header().Set("Content-Type", t)
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
@@ -4,7 +4,12 @@ import (
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-point/types"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -114,3 +119,327 @@ func (hd *Handlers) buildPointBalanceHal(contract, account string, amount common
 
 	return hal, nil
 }
+
+var defaultPointLeaderboardDays int64 = 7
+
+func (hd *Handlers) handlePointLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	var blocks, days int64
+	for _, i := range []struct {
+		name string
+		v    *int64
+	}{{"blocks", &blocks}, {"days", &days}} {
+		s := strings.TrimSpace(r.URL.Query().Get(i.name))
+		if len(s) < 1 {
+			continue
+		}
+
+		j, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || j < 1 {
+			currencydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid %s, %q", i.name, s), http.StatusBadRequest)
+
+			return
+		}
+		*i.v = j
+	}
+
+	switch {
+	case blocks > 0 && days > 0:
+		currencydigest.HTTP2ProblemWithError(w, errors.Errorf("blocks and days can not be given together"), http.StatusBadRequest)
+
+		return
+	case blocks < 1 && days < 1:
+		days = defaultPointLeaderboardDays
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{
+		r.URL.Path,
+		"blocks=" + strconv.FormatInt(blocks, 10),
+		"days=" + strconv.FormatInt(days, 10),
+	}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handlePointLeaderboardInGroup(contract, blocks, days, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+// handlePointLeaderboardInGroup ranks the accounts in the window which ends
+// at the height of the page, so the ranks do not change between the pages.
+func (hd *Handlers) handlePointLeaderboardInGroup(contract string, blocks, days int64, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "point-leaderboard")
+
+	var fromHeight base.Height
+	var since *time.Time
+
+	if blocks > 0 {
+		fromHeight = q.height - base.Height(blocks) + 1
+		if fromHeight < base.GenesisHeight {
+			fromHeight = base.GenesisHeight
+		}
+	} else {
+		now, err := BlockTime(hd.database, q.height)
+		if err != nil {
+			return nil, false, err
+		}
+
+		t := now.Add(-time.Hour * 24 * time.Duration(days))
+		since = &t
+
+		if fromHeight, err = BlockHeightSince(hd.database, t, q.height); err != nil {
+			return nil, false, err
+		}
+	}
+
+	all, err := PointLeaderboard(hd.database, contract, &fromHeight, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "point leaderboard by contract %s", contract)
+	case len(all) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("point leaderboard by contract %s", contract)
+	}
+
+	ranks, first, last, filled, err := pageItems(all, func(i PointRank) string { return i.Address }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(ranks))
+	for i := range ranks {
+		h, err := hd.combineURL(HandlerPathPointActivity, "contract", contract, "address", ranks[i].Address)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = currencydigest.NewBaseHal(ranks[i], currencydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathPointLeaderboard, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if blocks > 0 {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, "blocks="+strconv.FormatInt(blocks, 10))
+	} else {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, "days="+strconv.FormatInt(days, 10))
+	}
+
+	window := struct {
+		FromHeight base.Height          `json:"from_height"`
+		Since      *time.Time           `json:"since,omitempty"`
+		Ranks      []currencydigest.Hal `json:"ranks"`
+	}{FromHeight: fromHeight, Since: since, Ranks: vas}
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(window, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathPoint, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("point", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func parsePointActivityOffset(s string) (*PointActivity, error) {
+	i := strings.Index(s, ",")
+	if i < 1 {
+		return nil, errors.Errorf("invalid offset, %q", s)
+	}
+
+	height, err := base.ParseHeightString(s[:i])
+	if err != nil {
+		return nil, errors.Errorf("invalid offset, %q", s)
+	}
+
+	index, err := strconv.ParseUint(s[i+1:], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid offset, %q", s)
+	}
+
+	return &PointActivity{Height: height, Index: index}, nil
+}
+
+func pointActivityOffset(height base.Height, index uint64) string {
+	return height.String() + "," + strconv.FormatUint(index, 10)
+}
+
+func (hd *Handlers) handlePointActivity(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	var parsedOffset *PointActivity
+	if len(q.offset) > 0 {
+		i, err := parsePointActivityOffset(q.offset)
+		if err != nil {
+			currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+			return
+		}
+		parsedOffset = i
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	address, err, status := parseRequest(w, r, "address")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handlePointActivityInGroup(contract, address, parsedOffset, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		hd.Log().Err(err).Str("contract", contract).Str("address", address).Msg("failed to get point activity")
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handlePointActivityInGroup(
+	contract, address string,
+	parsedOffset *PointActivity,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "point-activity")
+
+	var vas []currencydigest.Hal
+	var keys []string
+	if err := PointActivities(
+		hd.database, contract, address, parsedOffset, q.scanReverse(), limit, &q.height,
+		func(i PointActivity) (bool, error) {
+			h, err := hd.combineURL(currencydigest.HandlerPathOperation, "hash", i.FactHash)
+			if err != nil {
+				return false, err
+			}
+
+			vas = append(vas, currencydigest.NewBaseHal(i, currencydigest.NewHalLink(h, nil)))
+			keys = append(keys, pointActivityOffset(i.Height, i.Index))
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "point activity by contract %s, account %s", contract, address)
+	} else if len(vas) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("point activity by contract %s, account %s", contract, address)
+	}
+
+	if q.prev {
+		reverseItems(vas)
+		reverseItems(keys)
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathPointActivity, "contract", contract, "address", address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	filled := int64(len(vas)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		keys[0], keys[len(keys)-1],
+		filled,
+		func() (int64, error) {
+			return PointActivityCount(hd.database, contract, address, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathPointBalance, "contract", contract, "address", address)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("balance", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
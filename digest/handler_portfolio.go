@@ -0,0 +1,77 @@
+package digest
+
+import (
+	"net/http"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+)
+
+func (hd *Handlers) handleAccountPortfolio(w http.ResponseWriter, r *http.Request) {
+	cacheKey := currencydigest.CacheKeyPath(r)
+	if err := currencydigest.LoadFromCache(hd.cache, cacheKey, w); err == nil {
+		return
+	}
+
+	address, err, status := parseRequest(w, r, "address")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cacheKey, func() (interface{}, error) {
+		return hd.handleAccountPortfolioInGroup(address)
+	}); err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+	} else {
+		currencydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			currencydigest.HTTP2WriteCache(w, cacheKey, time.Second*3)
+		}
+	}
+}
+
+func (hd *Handlers) handleAccountPortfolioInGroup(address string) (interface{}, error) {
+	now, err := LastBlockTime(hd.database)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio, err := AccountPortfolio(hd.database, address, now)
+	if err != nil {
+		return nil, mitumutil.ErrNotFound.WithMessage(err, "portfolio, account %s", address)
+	}
+
+	h, err := hd.combineURL(HandlerPathAccountPortfolio, "address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(portfolio, currencydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(currencydigest.HandlerPathAccount, "address", address)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("account", currencydigest.NewHalLink(h, nil))
+
+	for i := range portfolio.Tokens {
+		h, err := hd.combineURL(HandlerPathTokenBalance, "contract", portfolio.Tokens[i].Contract, "address", address)
+		if err != nil {
+			return nil, err
+		}
+		hal = hal.AddLink("token:"+portfolio.Tokens[i].Contract, currencydigest.NewHalLink(h, nil))
+	}
+
+	for i := range portfolio.Points {
+		h, err := hd.combineURL(HandlerPathPointBalance, "contract", portfolio.Points[i].Contract, "address", address)
+		if err != nil {
+			return nil, err
+		}
+		hal = hal.AddLink("point:"+portfolio.Points[i].Contract, currencydigest.NewHalLink(h, nil))
+	}
+
+	return hd.encoder.Marshal(hal)
+}
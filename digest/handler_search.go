@@ -0,0 +1,160 @@
+package digest
+
+import (
+	"net/http"
+	"strings"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+func (hd *Handlers) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(query) < 1 {
+		currencydigest.HTTP2ProblemWithError(w, errors.Errorf("empty query, q"), http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path, "q=" + query}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleSearchInGroup(query, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+// searchResultKey is the offset of the result; the same id can be found in
+// the different kinds and contracts.
+func searchResultKey(result SearchResult) string {
+	return strings.Join([]string{result.Type, result.Contract, result.Template, result.ID}, ":")
+}
+
+func (hd *Handlers) handleSearchInGroup(query string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "search")
+
+	all, err := Search(hd.database, query)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "search, %q", query)
+	case len(all) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("search, %q", query)
+	}
+
+	results, first, last, filled, err := pageItems(all, searchResultKey, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(results))
+	for i := range results {
+		hal, err := hd.buildSearchResultHal(results[i])
+		if err != nil {
+			return nil, false, err
+		}
+
+		vas[i] = hal
+	}
+
+	h, err := hd.combineURL(HandlerPathSearch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	baseSelf := currencydigest.AddQueryValue(h, "q="+query)
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) buildSearchResultHal(result SearchResult) (currencydigest.Hal, error) {
+	var path string
+	var pairs []string
+
+	switch result.Type {
+	case SearchTypeBlock:
+		path, pairs = currencydigest.HandlerPathBlockByHeight, []string{"height", result.Height.String()}
+	case SearchTypeOperation:
+		path, pairs = currencydigest.HandlerPathOperation, []string{"hash", result.ID}
+	case SearchTypeAccount, SearchTypeContract:
+		path, pairs = currencydigest.HandlerPathAccount, []string{"address", result.ID}
+	case SearchTypeToken:
+		path, pairs = HandlerPathToken, []string{"contract", result.Contract}
+	case SearchTypePoint:
+		path, pairs = HandlerPathPoint, []string{"contract", result.Contract}
+	case SearchTypeNFT:
+		path, pairs = HandlerPathNFT, []string{"contract", result.Contract, "id", result.ID}
+	case SearchTypeCredential:
+		path, pairs = HandlerPathDIDCredential, []string{
+			"contract", result.Contract, "templateid", result.Template, "credentialid", result.ID}
+	default:
+		return nil, errors.Errorf("unknown search result type, %q", result.Type)
+	}
+
+	h, err := hd.combineURL(path, pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := currencydigest.NewBaseHal(result, currencydigest.NewHalLink(h, nil))
+
+	if result.Type == SearchTypeContract {
+		for _, m := range result.Models {
+			if path, found := contractModelPaths[m.Model]; found {
+				h, err := hd.combineURL(path, "contract", result.ID)
+				if err != nil {
+					return nil, err
+				}
+				hal = hal.AddLink(m.Model, currencydigest.NewHalLink(h, nil))
+			}
+		}
+	}
+
+	return hal, nil
+}
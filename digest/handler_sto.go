@@ -1,12 +1,17 @@
 package digest
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	crcydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	typesto "github.com/ProtoconNet/mitum-sto/types/sto"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -328,6 +333,75 @@ func (hd *Handlers) buildSTOPartitionBalanceHal(
 	return hal, nil
 }
 
+func (hd *Handlers) handleSTOPartitionControllers(w http.ResponseWriter, r *http.Request) {
+	cachekey := crcydigest.CacheKeyPath(r)
+	if err := crcydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	partition, err, status := parseRequest(w, r, "partition")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		return hd.handleSTOPartitionControllersInGroup(contract, partition)
+	}); err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+	} else {
+		crcydigest.HTTP2WriteHalBytes(hd.encoder, w, v.([]byte), http.StatusOK)
+		if !shared {
+			crcydigest.HTTP2WriteCache(w, cachekey, time.Millisecond*500)
+		}
+	}
+}
+
+func (hd *Handlers) handleSTOPartitionControllersInGroup(
+	contract, partition string,
+) (interface{}, error) {
+	switch controllers, err := STOPartitionControllers(hd.database, contract, partition); {
+	case err != nil:
+		return nil, util.ErrNotFound.WithMessage(
+			err, "sto partition controllers, contract %s, partition %s", contract, partition)
+	default:
+		hal, err := hd.buildSTOPartitionControllersHal(contract, partition, controllers)
+		if err != nil {
+			return nil, err
+		}
+		return hd.encoder.Marshal(hal)
+	}
+}
+
+func (hd *Handlers) buildSTOPartitionControllersHal(
+	contract, partition string, controllers []base.Address,
+) (crcydigest.Hal, error) {
+	h, err := hd.combineURL(HandlerPathSTOPartitionControllers, "contract", contract, "partition", partition)
+	if err != nil {
+		return nil, err
+	}
+
+	hal := crcydigest.NewBaseHal(struct {
+		Controllers []base.Address `json:"controllers"`
+	}{Controllers: controllers}, crcydigest.NewHalLink(h, nil))
+
+	h, err = hd.combineURL(HandlerPathSTOPartitionBalance, "contract", contract, "partition", partition)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("balance", crcydigest.NewHalLink(h, nil))
+
+	return hal, nil
+}
+
 func (hd *Handlers) handleSTOOperatorHolders(w http.ResponseWriter, r *http.Request) {
 	cachekey := crcydigest.CacheKeyPath(r)
 	if err := crcydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
@@ -389,3 +463,536 @@ func (hd *Handlers) buildSTOOperatorHoldersHal(
 
 	return hal, nil
 }
+
+var stoCapTableCSVCacheKeySuffix = ":csv"
+
+func (hd *Handlers) handleSTOCapTable(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(crcydigest.ParseStringQuery(r.URL.Query().Get("format")))
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		crcydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid format, %q", format), http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	// NOTE the height of the cursor is the height of the cap table.
+	if s := crcydigest.ParseStringQuery(r.URL.Query().Get("height")); len(s) > 0 && len(q.cursor) < 1 {
+		h, err := base.ParseHeightString(s)
+		if err != nil || h > q.height {
+			crcydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid height, %q", s), http.StatusBadRequest)
+
+			return
+		}
+		q.height = h
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	// NOTE the csv format is not a hal document, so it is not cached; it has
+	// the whole cap table.
+	if format == "csv" {
+		cachekey := crcydigest.CacheKey(r.URL.Path, "height="+q.height.String())
+
+		if v, err, _ := hd.rg.Do(cachekey+stoCapTableCSVCacheKeySuffix, func() (interface{}, error) {
+			return hd.handleSTOCapTableCSVInGroup(contract, q.height)
+		}); err != nil {
+			crcydigest.HTTP2HandleError(w, err)
+		} else {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition",
+				`attachment; filename="captable-`+contract+`-`+q.height.String()+`.csv"`)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(v.([]byte))
+		}
+
+		return
+	}
+
+	cachekey := crcydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := crcydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleSTOCapTableInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	crcydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		crcydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) stoCapTable(contract string, height base.Height) (*STOCapTable, error) {
+	switch table, err := STOCapTableAt(hd.database, contract, height); {
+	case err != nil:
+		return nil, util.ErrNotFound.WithMessage(err, "sto cap table, contract %s, height %s", contract, height)
+	case len(table.Holders) < 1:
+		return nil, util.ErrNotFound.Errorf("sto cap table, contract %s, height %s", contract, height)
+	default:
+		return table, nil
+	}
+}
+
+func (hd *Handlers) handleSTOCapTableInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "sto-captable")
+
+	table, err := hd.stoCapTable(contract, q.height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	holders, first, last, filled, err := pageItems(
+		table.Holders, func(i STOCapTableHolder) string { return i.Holder }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathSTOCapTable, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	baseSelf := crcydigest.AddQueryValue(h, "height="+q.height.String())
+
+	page := *table
+	page.Holders = holders
+
+	hal, err := hd.addPageLinks(
+		crcydigest.NewBaseHal(page, crcydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(table.Holders)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hal = hal.AddLink("csv", crcydigest.NewHalLink(crcydigest.AddQueryValue(baseSelf, "format=csv"), nil))
+
+	h, err = hd.combineURL(HandlerPathSTOService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", crcydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) handleSTOCapTableCSVInGroup(contract string, height base.Height) (interface{}, error) {
+	table, err := hd.stoCapTable(contract, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+
+	if err := cw.Write([]string{"holder", "partition", "balance", "percentage", "operators"}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range table.Holders {
+		holder := table.Holders[i]
+
+		for j := range holder.Partitions {
+			pt := holder.Partitions[j]
+
+			if err := cw.Write([]string{
+				holder.Holder,
+				pt.Partition,
+				pt.Balance.String(),
+				pt.Percentage,
+				strings.Join(pt.Operators, ";"),
+			}); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (hd *Handlers) handleSTOPartitions(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := crcydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := crcydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleSTOPartitionsInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	crcydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		crcydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleSTOPartitionsInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "sto-partitions")
+
+	all, err := STOPartitions(hd.database, contract, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, util.ErrNotFound.WithMessage(err, "sto partitions, contract %s", contract)
+	case len(all) < 1:
+		return nil, false, util.ErrNotFound.Errorf("sto partitions, contract %s", contract)
+	}
+
+	partitions, first, last, filled, err := pageItems(
+		all, func(i STOPartition) string { return i.Partition }, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]crcydigest.Hal, len(partitions))
+	for i := range partitions {
+		h, err := hd.combineURL(
+			HandlerPathSTOPartitionHolders, "contract", contract, "partition", partitions[i].Partition)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hal := crcydigest.NewBaseHal(partitions[i], crcydigest.NewHalLink(h, nil))
+
+		h, err = hd.combineURL(
+			HandlerPathSTOPartitionBalance, "contract", contract, "partition", partitions[i].Partition)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = hal.AddLink("balance", crcydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathSTOPartitions, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hal, err := hd.addPageLinks(
+		crcydigest.NewBaseHal(vas, crcydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathSTOService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", crcydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) handleSTOPartitionHolders(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	partition, err, status := parseRequest(w, r, "partition")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := crcydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := crcydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleSTOPartitionHoldersInGroup(contract, partition, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	crcydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		crcydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleSTOPartitionHoldersInGroup(
+	contract, partition string,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "sto-partition-holders")
+
+	var vas []crcydigest.Hal
+	var read int64
+	var first, last string
+	if err := STOPartitionHolders(
+		hd.database, contract, partition, q.offset, q.scanReverse(), limit, &q.height,
+		func(holder *STOPartitionHolder, l string) error {
+			if read < 1 {
+				first = l
+			}
+
+			read++
+			last = l
+
+			if holder == nil {
+				return nil
+			}
+
+			h, err := hd.combineURL(
+				HandlerPathSTOHolderPartitionBalance,
+				"contract", contract, "address", holder.Holder, "partition", partition,
+			)
+			if err != nil {
+				return err
+			}
+
+			vas = append(vas, crcydigest.NewBaseHal(*holder, crcydigest.NewHalLink(h, nil)))
+
+			return nil
+		},
+	); err != nil {
+		return nil, false, util.ErrNotFound.WithMessage(
+			err, "sto partition holders, contract %s, partition %s", contract, partition)
+	} else if read < 1 {
+		return nil, false, util.ErrNotFound.Errorf(
+			"sto partition holders, contract %s, partition %s", contract, partition)
+	}
+
+	if q.prev {
+		reverseItems(vas)
+		first, last = last, first
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathSTOPartitionHolders, "contract", contract, "partition", partition)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if vas == nil {
+		vas = []crcydigest.Hal{}
+	}
+
+	filled := read == limit
+
+	hal, err := hd.addPageLinks(
+		crcydigest.NewBaseHal(vas, crcydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			var n int64
+
+			err := STOPartitionHolders(hd.database, contract, partition, "", false, 0, &q.height,
+				func(holder *STOPartitionHolder, _ string) error {
+					if holder != nil {
+						n++
+					}
+
+					return nil
+				},
+			)
+
+			return n, err
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathSTOPartitionBalance, "contract", contract, "partition", partition)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("balance", crcydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+var maxSTOCanTransferBodySize int64 = 1 << 13
+
+func (hd *Handlers) handleSTOCanTransfer(w http.ResponseWriter, r *http.Request) {
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	var body struct {
+		Sender    string `json:"sender"`
+		Receiver  string `json:"receiver"`
+		Partition string `json:"partition"`
+		Amount    string `json:"amount"`
+		Operator  string `json:"operator"`
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSTOCanTransferBodySize)).Decode(&body); err != nil {
+		crcydigest.HTTP2ProblemWithError(w, errors.Wrap(err, "invalid request body"), http.StatusBadRequest)
+
+		return
+	}
+
+	check := STOTransferCheck{
+		Sender:    strings.TrimSpace(body.Sender),
+		Receiver:  strings.TrimSpace(body.Receiver),
+		Partition: strings.TrimSpace(body.Partition),
+		Operator:  strings.TrimSpace(body.Operator),
+	}
+
+	for _, f := range []struct {
+		name, v string
+	}{{"sender", check.Sender}, {"receiver", check.Receiver}, {"partition", check.Partition}} {
+		if len(f.v) < 1 {
+			crcydigest.HTTP2ProblemWithError(w, errors.Errorf("empty %s", f.name), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	amount, err := common.NewBigFromString(strings.TrimSpace(body.Amount))
+	if err != nil {
+		crcydigest.HTTP2ProblemWithError(w, errors.Errorf("invalid amount, %q", body.Amount), http.StatusBadRequest)
+
+		return
+	}
+	check.Amount = amount
+
+	result, err := STOCanTransfer(hd.database, contract, check)
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	h, err := hd.combineURL(HandlerPathSTOCanTransfer, "contract", contract)
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	hal := crcydigest.NewBaseHal(result, crcydigest.NewHalLink(h, nil))
+
+	if h, err = hd.combineURL(
+		HandlerPathSTOHolderPartitionBalance,
+		"contract", contract, "address", check.Sender, "partition", check.Partition,
+	); err == nil {
+		hal = hal.AddLink("balance", crcydigest.NewHalLink(h, nil))
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+	if err != nil {
+		crcydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	crcydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+}
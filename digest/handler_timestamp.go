@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 )
@@ -190,3 +191,412 @@ func (hd *Handlers) buildTimeStampItem(contract string, it types.TimeStampItem,
 
 	return hal, nil
 }
+
+func (hd *Handlers) handleTimeStampProjects(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTimeStampProjectsInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleTimeStampProjectsInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "timestamp-projects")
+
+	projects, err := TimestampProjects(hd.database, contract, q.offset, q.scanReverse(), limit, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "timestamp projects by contract %s", contract)
+	case len(projects) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("timestamp projects by contract %s", contract)
+	}
+
+	if q.prev {
+		reverseItems(projects)
+	}
+
+	vas := make([]currencydigest.Hal, len(projects))
+	for i := range projects {
+		h, err := hd.combineURL(HandlerPathTimeStampItems, "contract", contract, "project", projects[i].Project)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hal := currencydigest.NewBaseHal(projects[i], currencydigest.NewHalLink(h, nil))
+
+		h, err = hd.combineURL(
+			HandlerPathTimeStampItem,
+			"contract", contract,
+			"project", projects[i].Project,
+			"tid", strconv.FormatUint(projects[i].LastTimestampIdx, 10),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = hal.AddLink("last", currencydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathTimeStampProjects, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	filled := int64(len(projects)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		projects[0].Project, projects[len(projects)-1].Project,
+		filled,
+		func() (int64, error) {
+			return TimestampProjectCount(hd.database, contract, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathTimeStampService, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("service", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func parseTimeStampQuery(s, name string) (*uint64, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return nil, nil
+	}
+
+	i, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid %s, %q", name, s)
+	}
+
+	return &i, nil
+}
+
+func (hd *Handlers) handleTimeStampItems(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	offset, err := parseTimeStampQuery(q.offset, "offset")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	var from, to *uint64
+	for _, i := range []struct {
+		name string
+		v    **uint64
+	}{{"from", &from}, {"to", &to}} {
+		j, err := parseTimeStampQuery(r.URL.Query().Get(i.name), i.name)
+		if err != nil {
+			currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+			return
+		}
+		*i.v = j
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	project, err, status := parseRequest(w, r, "project")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(
+		append(append([]string{r.URL.Path}, q.cacheKeys()...), timeStampItemsQuery(from, to)...)...,
+	)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTimeStampItemsInGroup(contract, project, offset, from, to, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		hd.Log().Err(err).Str("contract", contract).Str("project", project).Msg("failed to get timestamp items")
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+// timeStampItemsQuery is the range of request timestamp; it is kept in the
+// links of pages.
+func timeStampItemsQuery(from, to *uint64) []string {
+	var query []string
+	if from != nil {
+		query = append(query, "from="+strconv.FormatUint(*from, 10))
+	}
+	if to != nil {
+		query = append(query, "to="+strconv.FormatUint(*to, 10))
+	}
+
+	return query
+}
+
+func (hd *Handlers) handleTimeStampItemsInGroup(
+	contract, project string,
+	offset, from, to *uint64,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "timestamp-items")
+
+	var vas []currencydigest.Hal
+	var first, last string
+	if err := TimestampItemsByProject(
+		hd.database, contract, project, offset, q.scanReverse(), limit, from, to, &q.height,
+		func(it types.TimeStampItem, st base.State) (bool, error) {
+			hal, err := hd.buildTimeStampItem(contract, it, st)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+
+			if len(first) < 1 {
+				first = strconv.FormatUint(it.TimestampID(), 10)
+			}
+			last = strconv.FormatUint(it.TimestampID(), 10)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "timestamp items by contract %s, project %s", contract, project)
+	} else if len(vas) < 1 {
+		return nil, false, mitumutil.ErrNotFound.Errorf("timestamp items by contract %s, project %s", contract, project)
+	}
+
+	if q.prev {
+		reverseItems(vas)
+		first, last = last, first
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathTimeStampItems, "contract", contract, "project", project)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, i := range timeStampItemsQuery(from, to) {
+		baseSelf = currencydigest.AddQueryValue(baseSelf, i)
+	}
+
+	filled := int64(len(vas)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		first, last,
+		filled,
+		nil,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) handleTimeStampLookup(w http.ResponseWriter, r *http.Request) {
+	hash := strings.TrimSpace(r.URL.Query().Get("hash"))
+	if len(hash) < 1 {
+		currencydigest.HTTP2ProblemWithError(w, errors.Errorf("empty hash"), http.StatusBadRequest)
+
+		return
+	}
+
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path, "hash=" + hash}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTimeStampLookupInGroup(contract, hash, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func timestampMatchKey(m TimestampMatch) string {
+	return m.Project + ":" + strconv.FormatUint(m.TimestampIdx, 10)
+}
+
+func (hd *Handlers) handleTimeStampLookupInGroup(contract, hash string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "timestamp-lookup")
+
+	all, err := TimestampItemsByDataHash(hd.database, contract, hash, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "timestamp items by contract %s, hash %s", contract, hash)
+	case len(all) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("timestamp items by contract %s, hash %s", contract, hash)
+	}
+
+	matches, first, last, filled, err := pageItems(all, timestampMatchKey, q, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	vas := make([]currencydigest.Hal, len(matches))
+	for i := range matches {
+		h, err := hd.combineURL(
+			HandlerPathTimeStampItem,
+			"contract", contract,
+			"project", matches[i].Project,
+			"tid", strconv.FormatUint(matches[i].TimestampIdx, 10),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		hal := currencydigest.NewBaseHal(matches[i], currencydigest.NewHalLink(h, nil))
+
+		h, err = hd.combineURL(currencydigest.HandlerPathBlockByHeight, "height", matches[i].Height.String())
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = hal.AddLink("block", currencydigest.NewHalLink(h, nil))
+	}
+
+	h, err := hd.combineURL(HandlerPathTimeStampLookup, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	baseSelf := currencydigest.AddQueryValue(h, "hash="+hash)
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(
+			struct {
+				Hash    string               `json:"hash"`
+				Matches []currencydigest.Hal `json:"matches"`
+			}{Hash: hash, Matches: vas},
+			currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil),
+		),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return int64(len(all)), nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
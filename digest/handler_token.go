@@ -4,6 +4,8 @@ import (
 	"github.com/ProtoconNet/mitum-currency/v3/common"
 	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"github.com/ProtoconNet/mitum-token/types"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"net/http"
 	"time"
 )
@@ -114,3 +116,237 @@ func (hd *Handlers) buildTokenBalanceHal(contract, account string, amount common
 
 	return hal, nil
 }
+
+func (hd *Handlers) handleTokenHolders(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTokenHoldersInGroup(contract, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleTokenHoldersInGroup(contract string, q pageQuery) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "token-holders")
+
+	holders, err := TokenHolders(hd.database, contract, q.offset, q.scanReverse(), limit, &q.height)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "token holders by contract %s", contract)
+	case len(holders) < 1:
+		return nil, false, mitumutil.ErrNotFound.Errorf("token holders by contract %s", contract)
+	}
+
+	if q.prev {
+		reverseItems(holders)
+	}
+
+	vas := make([]currencydigest.Hal, len(holders))
+	for i := range holders {
+		h, err := hd.combineURL(HandlerPathTokenBalance, "contract", contract, "address", holders[i].Address)
+		if err != nil {
+			return nil, false, err
+		}
+		vas[i] = currencydigest.NewBaseHal(holders[i], currencydigest.NewHalLink(h, nil))
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathTokenHolders, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	filled := int64(len(holders)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(vas, currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil)),
+		baseSelf, q,
+		TokenHolderKey(holders[0]), TokenHolderKey(holders[len(holders)-1]),
+		filled,
+		func() (int64, error) {
+			return TokenHolderCount(hd.database, contract, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathToken, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("token", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
+
+func (hd *Handlers) handleTokenSupply(w http.ResponseWriter, r *http.Request) {
+	q, err := hd.parsePageQuery(r)
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	var offset *base.Height
+	if len(q.offset) > 0 {
+		height, err := base.ParseHeightString(q.offset)
+		if err != nil {
+			currencydigest.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+			return
+		}
+		offset = &height
+	}
+
+	contract, err, status := parseRequest(w, r, "contract")
+	if err != nil {
+		currencydigest.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cachekey := currencydigest.CacheKey(append([]string{r.URL.Path}, q.cacheKeys()...)...)
+	if err := currencydigest.LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		i, filled, err := hd.handleTokenSupplyInGroup(contract, offset, q)
+
+		return []interface{}{i, filled}, err
+	})
+
+	if err != nil {
+		currencydigest.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	var b []byte
+	var filled bool
+	{
+		l := v.([]interface{})
+		b = l[0].([]byte)
+		filled = l[1].(bool)
+	}
+
+	currencydigest.HTTP2WriteHalBytes(hd.encoder, w, b, http.StatusOK)
+
+	if !shared {
+		currencydigest.HTTP2WriteCache(w, cachekey, q.expire(hd.expireNotFilled, filled))
+	}
+}
+
+func (hd *Handlers) handleTokenSupplyInGroup(
+	contract string,
+	offset *base.Height,
+	q pageQuery,
+) ([]byte, bool, error) {
+	limit := hd.pageLimit(q, "token-supply")
+
+	current, err := LatestTokenSupply(hd.database, contract)
+	switch {
+	case err != nil:
+		return nil, false, mitumutil.ErrNotFound.WithMessage(err, "token supply by contract %s", contract)
+	case current == nil:
+		return nil, false, mitumutil.ErrNotFound.Errorf("token supply by contract %s", contract)
+	}
+
+	var series []TokenSupply
+	if err := TokenSupplySeries(
+		hd.database, contract, offset, q.scanReverse(), limit, &q.height,
+		func(i TokenSupply) (bool, error) {
+			series = append(series, i)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, err
+	}
+
+	if q.prev {
+		reverseItems(series)
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathTokenSupply, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var first, last string
+	if len(series) > 0 {
+		first = series[0].Height.String()
+		last = series[len(series)-1].Height.String()
+	}
+
+	filled := int64(len(series)) == limit
+
+	hal, err := hd.addPageLinks(
+		currencydigest.NewBaseHal(
+			struct {
+				TokenSupply
+				Series []TokenSupply `json:"series"`
+			}{TokenSupply: *current, Series: series},
+			currencydigest.NewHalLink(q.selfPageURL(baseSelf), nil),
+		),
+		baseSelf, q,
+		first, last,
+		filled,
+		func() (int64, error) {
+			return TokenSupplyCount(hd.database, contract, &q.height)
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := hd.combineURL(HandlerPathToken, "contract", contract)
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("token", currencydigest.NewHalLink(h, nil))
+
+	b, err := hd.encoder.Marshal(hal)
+
+	return b, filled, err
+}
@@ -1,6 +1,9 @@
 package digest
 
 import (
+	"context"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -8,66 +11,137 @@ import (
 
 var indexPrefix = "mitum_digest_"
 
-var accountIndexModels = []mongo.IndexModel{
+var timestampIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "address", Value: 1}, bson.E{Key: "height", Value: -1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "project", Value: 1},
+			bson.E{Key: "timestampidx", Value: 1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_account"),
+			SetName(indexPrefix + "timestamp_item"),
 	},
 	{
-		Keys: bson.D{bson.E{Key: "height", Value: -1}},
+		Keys: bson.D{bson.E{Key: "contract", Value: 1}, bson.E{Key: "data_hash", Value: 1}},
 		Options: options.Index().
-			SetName("mitum_digest_account_height"),
+			SetName(indexPrefix + "timestamp_data_hash"),
 	},
+}
+
+var pointActivityIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "pubs", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "address", Value: 1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "address", Value: 1},
+			bson.E{Key: "height", Value: 1},
+			bson.E{Key: "index", Value: 1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_account_publiskeys"),
+			SetName(indexPrefix + "point_activity_account"),
 	},
-}
-
-var balanceIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "address", Value: 1}, bson.E{Key: "height", Value: -1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "earned", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_balance"),
+			SetName(indexPrefix + "point_activity_earned"),
 	},
 	{
 		Keys: bson.D{
+			bson.E{Key: "fact_hash", Value: 1},
 			bson.E{Key: "address", Value: 1},
-			bson.E{Key: "currency", Value: 1},
-			bson.E{Key: "height", Value: -1},
+			bson.E{Key: "kind", Value: 1},
 		},
 		Options: options.Index().
-			SetName("mitum_digest_balance_currency"),
+			SetName(indexPrefix + "point_activity_fact"),
 	},
+}
+
+var credentialStatusIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "height", Value: -1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "template", Value: 1},
+			bson.E{Key: "credential_id", Value: 1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_balance_height"),
+			SetName(indexPrefix + "did_credential_status"),
 	},
 }
 
-var operationIndexModels = []mongo.IndexModel{
+var credentialStatusListIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "addresses", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "template", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_account_operation"),
+			SetName(indexPrefix + "did_status_list"),
 	},
+}
+
+var credentialIndexModels = []mongo.IndexModel{
 	{
-		Keys: bson.D{bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "template", Value: 1},
+			bson.E{Key: "credential_id", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
 		Options: options.Index().
-			SetName("mitum_digest_operation"),
+			SetName(indexPrefix + "did_credential"),
 	},
 	{
-		Keys: bson.D{bson.E{Key: "height", Value: -1}},
+		Keys: bson.D{bson.E{Key: "d.value.credential.holder", Value: 1}},
 		Options: options.Index().
-			SetName("mitum_digest_operation_height"),
+			SetName(indexPrefix + "did_credential_holder"),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "credential_id", Value: 1}},
+		Options: options.Index().
+			SetName(indexPrefix + "did_credential_id"),
+	},
+}
+
+var daoDelegatorsIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "proposal_id", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
+		Options: options.Index().
+			SetName(indexPrefix + "dao_delegators"),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "delegator_accounts", Value: 1}},
+		Options: options.Index().
+			SetName(indexPrefix + "dao_delegators_account"),
 	},
 }
 
 var defaultIndexes = map[string] /* collection */ []mongo.IndexModel{
-	defaultColNameAccount:   accountIndexModels,
-	defaultColNameBalance:   balanceIndexModels,
-	defaultColNameOperation: operationIndexModels,
+	defaultColNameTimeStamp:           timestampIndexModels,
+	defaultColNamePointActivity:       pointActivityIndexModels,
+	defaultColNameDIDCredential:       credentialIndexModels,
+	defaultColNameDIDCredentialStatus: credentialStatusIndexModels,
+	defaultColNameDIDStatusList:       credentialStatusListIndexModels,
+	defaultColNameDAODelegators:       daoDelegatorsIndexModels,
+}
+
+// CreateIndexes creates the indexes of the collections of this digest. The
+// indexes of the currency collections, like account, balance and operation,
+// are left to mitum-currency. Existing indexes with the same name and keys are
+// left as they are.
+func CreateIndexes(ctx context.Context, st *currencydigest.Database) error {
+	for col := range defaultIndexes {
+		if _, err := st.DatabaseClient().Collection(col).Indexes().CreateMany(ctx, defaultIndexes[col]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
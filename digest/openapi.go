@@ -0,0 +1,540 @@
+package digest
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	mitumbase "github.com/ProtoconNet/mitum2/base"
+	"github.com/gorilla/mux"
+)
+
+var openAPIVersion = "3.0.3"
+
+// openAPIRoute describes the route of handler in the OpenAPI document. The
+// embedded is the sample of the value embedded in the HAL response; nil is
+// any object. The raw route responds the embedded without HAL.
+type openAPIRoute struct {
+	tag      string
+	summary  string
+	queries  []string
+	embedded interface{}
+	list     bool
+	raw      bool
+	body     interface{}
+}
+
+var openAPIQueries = map[string]map[string]interface{}{
+	"limit":         {"type": "integer", "minimum": 1, "maximum": maxLimit, "description": "maximum number of items"},
+	"offset":        {"type": "string", "description": "offset of the next page; the sort key of the last item"},
+	"reverse":       {"type": "boolean", "description": "reverse the order of items"},
+	"cursor":        {"type": "string", "description": "cursor of the page, from the next or prev link"},
+	"total":         {"type": "boolean", "description": "count the items of the whole list"},
+	"height":        {"type": "integer", "minimum": 0, "description": "block height"},
+	"format":        {"type": "string", "enum": []string{"json", "csv"}},
+	"status":        {"type": "string", "description": "status of items"},
+	"model":         {"type": "string", "enum": contractModelNames},
+	"q":             {"type": "string", "description": "search query"},
+	"hash":          {"type": "string", "description": "data hash"},
+	"within":        {"type": "string", "description": "duration, like 24h"},
+	"from":          {"type": "integer", "minimum": 0, "description": "request timestamp from, inclusive"},
+	"to":            {"type": "integer", "minimum": 0, "description": "request timestamp to, inclusive"},
+	"blocks":        {"type": "integer", "minimum": 1, "description": "count in the last blocks; not with days"},
+	"days":          {"type": "integer", "minimum": 1, "description": "count in the last days; not with blocks"},
+	"facthash":      {"type": "string", "description": "fact hash of operation"},
+	"proposer":      {"type": "string", "description": "address of proposer"},
+	"option":        {"type": "string", "description": "option of proposal"},
+	"query":         {"type": "string", "description": "graphql query"},
+	"operationName": {"type": "string", "description": "graphql operation name"},
+	"variables":     {"type": "string", "description": "graphql variables in json"},
+}
+
+var openAPIListQueries = []string{"cursor", "offset", "reverse", "limit", "total"}
+
+var openAPIRoutes = map[string]openAPIRoute{
+	HandlerPathAccountPortfolio: {tag: "account", summary: "Holdings of account over every model", embedded: Portfolio{}},
+	HandlerPathContracts: {
+		tag: "contract", summary: "Contract accounts", embedded: ContractInfo{}, list: true,
+		queries: append([]string{"model"}, openAPIListQueries...),
+	},
+	HandlerPathSearch: {
+		tag: "search", summary: "Search by hash, height, address, symbol or id", embedded: SearchResult{}, list: true,
+		queries: append([]string{"q"}, openAPIListQueries...),
+	},
+	HandlerPathGraphQL: {
+		tag: "graphql", summary: "GraphQL query", embedded: GraphQLResponse{}, raw: true,
+		queries: []string{"query", "operationName", "variables"}, body: GraphQLRequest{},
+	},
+	HandlerPathOpenAPI:       {tag: "openapi", summary: "OpenAPI document", raw: true},
+	HandlerPathSwaggerUI:     {tag: "openapi", summary: "Swagger UI", raw: true},
+	HandlerPathNFTCollection: {tag: "nft", summary: "NFT collection design"},
+	HandlerPathNFTs: {
+		tag: "nft", summary: "NFTs of collection", list: true,
+		queries: append([]string{"facthash"}, openAPIListQueries...),
+	},
+	HandlerPathNFTCount:      {tag: "nft", summary: "Number of NFTs in collection"},
+	HandlerPathNFTOperators:  {tag: "nft", summary: "Operators of account"},
+	HandlerPathNFT:           {tag: "nft", summary: "NFT"},
+	HandlerPathDIDService:    {tag: "credential", summary: "Credential service design"},
+	HandlerPathDIDTemplates:  {tag: "credential", summary: "Templates of service", list: true, queries: openAPIListQueries},
+	HandlerPathDIDTemplate:   {tag: "credential", summary: "Template"},
+	HandlerPathDIDCredential: {tag: "credential", summary: "Credential"},
+	HandlerPathDIDCredentials: {
+		tag: "credential", summary: "Credentials of template", list: true,
+		queries: append([]string{"status"}, openAPIListQueries...),
+	},
+	HandlerPathDIDCredentialsExpiring: {
+		tag: "credential", summary: "Credentials expiring soon",
+		queries: append([]string{"within"}, openAPIListQueries...),
+	},
+	HandlerPathDIDStatusList: {tag: "credential", summary: "Revocation status list of template", embedded: CredentialStatusList{}},
+	HandlerPathDIDHolder: {
+		tag: "credential", summary: "Credentials of holder",
+		queries: append([]string{"status"}, openAPIListQueries...),
+	},
+	HandlerPathTimeStampService: {tag: "timestamp", summary: "Timestamp service design"},
+	HandlerPathTimeStampLookup: {
+		tag: "timestamp", summary: "Timestamp items by data hash",
+		queries: append([]string{"hash"}, openAPIListQueries...),
+	},
+	HandlerPathTimeStampProjects: {
+		tag: "timestamp", summary: "Projects of service", embedded: TimestampProject{}, list: true,
+		queries: openAPIListQueries,
+	},
+	HandlerPathTimeStampItems: {
+		tag: "timestamp", summary: "Items of project", list: true,
+		queries: append([]string{"from", "to"}, openAPIListQueries...),
+	},
+	HandlerPathTimeStampItem:   {tag: "timestamp", summary: "Timestamp item"},
+	HandlerPathToken:           {tag: "token", summary: "Token design"},
+	HandlerPathTokenBalance:    {tag: "token", summary: "Token balance of account"},
+	HandlerPathTokenAllowance:  {tag: "token", summary: "Token allowance of spender", embedded: Allowance{}},
+	HandlerPathTokenAllowances: {tag: "token", summary: "Token allowances of owner", embedded: Allowance{}, list: true, queries: openAPIListQueries},
+	HandlerPathTokenHolders:    {tag: "token", summary: "Token holders by balance", embedded: TokenHolder{}, list: true, queries: openAPIListQueries},
+	HandlerPathTokenSupply:     {tag: "token", summary: "Token supply series", embedded: TokenSupply{}, list: true, queries: openAPIListQueries},
+	HandlerPathPoint:           {tag: "point", summary: "Point design"},
+	HandlerPathPointBalance:    {tag: "point", summary: "Point balance of account"},
+	HandlerPathPointLeaderboard: {
+		tag: "point", summary: "Point leaderboard",
+		queries: append([]string{"blocks", "days"}, openAPIListQueries...),
+	},
+	HandlerPathPointActivity:   {tag: "point", summary: "Point activities of account", embedded: PointActivity{}, list: true, queries: openAPIListQueries},
+	HandlerPathPointAllowance:  {tag: "point", summary: "Point allowance of spender", embedded: Allowance{}},
+	HandlerPathPointAllowances: {tag: "point", summary: "Point allowances of owner", embedded: Allowance{}, list: true, queries: openAPIListQueries},
+	HandlerPathDAOService:      {tag: "dao", summary: "DAO design"},
+	HandlerPathDAOProposals: {
+		tag: "dao", summary: "Proposals", list: true,
+		queries: append([]string{"status", "proposer", "option"}, openAPIListQueries...),
+	},
+	HandlerPathDAOProposal:       {tag: "dao", summary: "Proposal"},
+	HandlerPathDAODelegator:      {tag: "dao", summary: "Delegator of proposal"},
+	HandlerPathDAOVoters:         {tag: "dao", summary: "Voters of proposal"},
+	HandlerPathDAOVotingPowerBox: {tag: "dao", summary: "Voting power box of proposal"},
+	HandlerPathDAOTally:          {tag: "dao", summary: "Tally of proposal", embedded: DAOTally{}},
+	HandlerPathDAOTimeline:       {tag: "dao", summary: "Timeline of proposal"},
+	HandlerPathDAOAccountGovernance: {
+		tag: "dao", summary: "Governance activities of account", embedded: DAOAccountGovernance{}, list: true,
+		queries: openAPIListQueries,
+	},
+	HandlerPathDAODelegations: {tag: "dao", summary: "Delegations of active proposals", queries: openAPIListQueries},
+	HandlerPathSTOService:     {tag: "sto", summary: "STO design"},
+	HandlerPathSTOCapTable: {
+		tag: "sto", summary: "Cap table; csv with format=csv", embedded: STOCapTable{},
+		queries: append([]string{"height", "format"}, openAPIListQueries...),
+	},
+	HandlerPathSTOPartitions: {
+		tag: "sto", summary: "Partitions", embedded: STOPartition{}, list: true,
+		queries: openAPIListQueries,
+	},
+	HandlerPathSTOCanTransfer: {
+		tag: "sto", summary: "Check transfer by partition", embedded: STOTransferCheckResult{},
+		body: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"sender", "receiver", "partition", "amount"},
+			"properties": map[string]interface{}{
+				"sender":    map[string]interface{}{"type": "string"},
+				"receiver":  map[string]interface{}{"type": "string"},
+				"partition": map[string]interface{}{"type": "string"},
+				"amount":    map[string]interface{}{"type": "string", "pattern": "^[0-9]+$"},
+				"operator":  map[string]interface{}{"type": "string"},
+			},
+		},
+	},
+	HandlerPathSTOPartitionHolders: {
+		tag: "sto", summary: "Holders of partition", embedded: STOPartitionHolder{}, list: true,
+		queries: openAPIListQueries,
+	},
+	HandlerPathSTOHolderPartitions:         {tag: "sto", summary: "Partitions of holder"},
+	HandlerPathSTOHolderPartitionBalance:   {tag: "sto", summary: "Partition balance of holder"},
+	HandlerPathSTOHolderPartitionOperators: {tag: "sto", summary: "Operators of holder partition"},
+	HandlerPathSTOPartitionBalance:         {tag: "sto", summary: "Balance of partition"},
+	HandlerPathSTOPartitionControllers:     {tag: "sto", summary: "Controllers of partition"},
+	HandlerPathSTOOperatorHolders:          {tag: "sto", summary: "Holders of operator"},
+}
+
+var (
+	openAPIBigType    = reflect.TypeOf(common.Big{})
+	openAPIHeightType = reflect.TypeOf(mitumbase.Height(0))
+	openAPITimeType   = reflect.TypeOf(time.Time{})
+	openAPIMarshaler  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// openAPIGenerator builds the OpenAPI document from the routes of router.
+type openAPIGenerator struct {
+	schemas map[string]interface{}
+}
+
+func (hd *Handlers) openAPIDocument() (map[string]interface{}, error) {
+	g := &openAPIGenerator{schemas: map[string]interface{}{
+		"HalLink": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"href":      map[string]interface{}{"type": "string"},
+				"templated": map[string]interface{}{"type": "boolean"},
+			},
+		},
+		"Problem": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"_hint":  map[string]interface{}{"type": "string"},
+				"type":   map[string]interface{}{"type": "string"},
+				"title":  map[string]interface{}{"type": "string"},
+				"detail": map[string]interface{}{"type": "string"},
+				"extra":  map[string]interface{}{"type": "object"},
+			},
+		},
+	}}
+
+	paths := map[string]interface{}{}
+
+	if err := hd.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil //nolint:nilerr //...
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet}
+		}
+
+		path, params := openAPIPath(tpl)
+
+		item := map[string]interface{}{}
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+
+			item[strings.ToLower(method)] = g.operation(tpl, method, params)
+		}
+
+		if len(item) > 0 {
+			paths[path] = item
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "mitum digest API",
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}, nil
+}
+
+func (g *openAPIGenerator) operation(tpl, method string, params []interface{}) map[string]interface{} {
+	route, found := openAPIRoutes[tpl]
+	if !found {
+		route = openAPIRoute{tag: "currency", summary: tpl}
+	}
+
+	parameters := append([]interface{}{}, params...)
+	for _, q := range route.queries {
+		if method != http.MethodGet {
+			break
+		}
+
+		parameters = append(parameters, map[string]interface{}{
+			"name":     q,
+			"in":       "query",
+			"required": q == "q",
+			"schema":   openAPIQueries[q],
+		})
+	}
+
+	var content map[string]interface{}
+	switch {
+	case route.raw && route.embedded == nil:
+		content = map[string]interface{}{"*/*": map[string]interface{}{}}
+	case route.raw:
+		content = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(route.embedded))},
+		}
+	default:
+		content = map[string]interface{}{
+			"application/hal+json": map[string]interface{}{"schema": g.hal(route)},
+		}
+	}
+
+	problem := map[string]interface{}{
+		"content": map[string]interface{}{
+			"application/problem+json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/Problem"},
+			},
+		},
+	}
+
+	op := map[string]interface{}{
+		"tags":       []string{route.tag},
+		"summary":    route.summary,
+		"parameters": parameters,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{"description": "OK", "content": content},
+			"400": mergeOpenAPI(problem, "description", "Bad Request"),
+			"404": mergeOpenAPI(problem, "description", "Not Found"),
+			"500": mergeOpenAPI(problem, "description", "Internal Server Error"),
+		},
+	}
+
+	if method == http.MethodPost && route.body != nil {
+		schema, ok := route.body.(map[string]interface{})
+		if !ok {
+			schema = g.schema(reflect.TypeOf(route.body))
+		}
+
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+			},
+		}
+	}
+
+	return op
+}
+
+func mergeOpenAPI(m map[string]interface{}, k string, v interface{}) map[string]interface{} {
+	n := map[string]interface{}{k: v}
+	for i := range m {
+		n[i] = m[i]
+	}
+
+	return n
+}
+
+// hal returns the schema of HAL response which embeds the value of route.
+func (g *openAPIGenerator) hal(route openAPIRoute) map[string]interface{} {
+	embedded := map[string]interface{}{"type": "object"}
+	if route.embedded != nil {
+		embedded = g.schema(reflect.TypeOf(route.embedded))
+	}
+
+	links := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"$ref": "#/components/schemas/HalLink"},
+	}
+
+	if route.list {
+		embedded = map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"_embedded": embedded,
+					"_links":    links,
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"_hint":     map[string]interface{}{"type": "string"},
+			"_embedded": embedded,
+			"_links":    links,
+			"_extra":    map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+// schema builds the json schema of t from the json tags of struct fields.
+// The named structs are added to the components.
+func (g *openAPIGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case openAPIBigType:
+		return map[string]interface{}{"type": "string", "pattern": "^-?[0-9]+$"}
+	case openAPIHeightType:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case openAPITimeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+	default:
+		return map[string]interface{}{}
+	}
+
+	// NOTE the types of mitum marshal themselves with hint.
+	if t.Implements(openAPIMarshaler) || reflect.PtrTo(t).Implements(openAPIMarshaler) {
+		return map[string]interface{}{"type": "object"}
+	}
+
+	name := t.Name()
+	if len(name) > 0 {
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		if _, found := g.schemas[name]; found {
+			return ref
+		}
+
+		g.schemas[name] = map[string]interface{}{} // NOTE for recursive types
+		g.schemas[name] = g.structSchema(t)
+
+		return ref
+	}
+
+	return g.structSchema(t)
+}
+
+func (g *openAPIGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && len(name) < 1 {
+			if s, ok := g.structSchema(indirectOpenAPIType(f.Type))["properties"].(map[string]interface{}); ok {
+				for k := range s {
+					properties[k] = s[k]
+				}
+			}
+
+			continue
+		}
+
+		if len(name) < 1 {
+			name = f.Name
+		}
+
+		properties[name] = g.schema(f.Type)
+
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	sort.Strings(required)
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+
+	return s
+}
+
+func indirectOpenAPIType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+
+	return t
+}
+
+// openAPIPath converts the path template of mux to the path of OpenAPI with
+// the path parameters.
+func openAPIPath(tpl string) (string, []interface{}) {
+	var path strings.Builder
+	var params []interface{}
+
+	for i := 0; i < len(tpl); i++ {
+		if tpl[i] != '{' {
+			path.WriteByte(tpl[i])
+
+			continue
+		}
+
+		depth, end := 0, -1
+		for j := i; j < len(tpl); j++ {
+			switch tpl[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+
+			if depth == 0 {
+				end = j
+
+				break
+			}
+		}
+
+		if end < 0 {
+			path.WriteString(tpl[i:])
+
+			break
+		}
+
+		name, pattern, _ := strings.Cut(tpl[i+1:end], ":")
+		path.WriteString("{" + name + "}")
+
+		schema := map[string]interface{}{"type": "string"}
+		if pattern = strings.ReplaceAll(pattern, "(?i)", ""); len(pattern) > 0 && pattern != ".*" && pattern != ".+" {
+			schema["pattern"] = "^" + pattern + "$"
+		}
+
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+
+		i = end
+	}
+
+	return path.String(), params
+}
@@ -0,0 +1,344 @@
+package digest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/gorilla/mux"
+)
+
+// openAPISources parses the go files of the package.
+func openAPISources(t *testing.T) []*ast.File {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
+
+// openAPIHandlerPaths evaluates the HandlerPath variables from the sources.
+func openAPIHandlerPaths(t *testing.T, files []*ast.File) map[string]string {
+	var eval func(ast.Expr) string
+	eval = func(e ast.Expr) string {
+		switch v := e.(type) {
+		case *ast.BasicLit:
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				t.Fatalf("failed to unquote %s: %v", v.Value, err)
+			}
+
+			return s
+		case *ast.BinaryExpr:
+			return eval(v.X) + eval(v.Y)
+		case *ast.SelectorExpr:
+			if v.Sel.Name == "REStringAddressString" {
+				return base.REStringAddressString
+			}
+		}
+
+		t.Fatalf("unknown expression in handler path, %T", e)
+
+		return ""
+	}
+
+	paths := map[string]string{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if strings.HasPrefix(name.Name, "HandlerPath") && i < len(vs.Values) {
+						paths[name.Name] = eval(vs.Values[i])
+					}
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// openAPIHandlerQueries returns the query parameters which the handlers of
+// the routes in setHandlers read, by the path template. The parameters are
+// collected from the Get calls on the URL query, also in the functions called
+// with the request.
+func openAPIHandlerQueries(t *testing.T) map[string][]string {
+	files := openAPISources(t)
+	paths := openAPIHandlerPaths(t, files)
+
+	funcs := map[string]*ast.FuncDecl{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+				funcs[fd.Name.Name] = fd
+			}
+		}
+	}
+
+	var collect func(string, map[string]bool, map[string]bool)
+	collect = func(name string, queries, visited map[string]bool) {
+		fd, found := funcs[name]
+		if !found || visited[name] {
+			return
+		}
+		visited[name] = true
+
+		// NOTE the names of queries read in the loop over the literal slice
+		// of struct, like `for _, q := range []struct{name string}{{"a"}}`.
+		ranged := map[string][]string{}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			rs, ok := n.(*ast.RangeStmt)
+			if !ok {
+				return true
+			}
+
+			v, ok := rs.Value.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			if cl, ok := rs.X.(*ast.CompositeLit); ok {
+				for _, elt := range cl.Elts {
+					if ecl, ok := elt.(*ast.CompositeLit); ok && len(ecl.Elts) > 0 {
+						if lit, ok := ecl.Elts[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							s, _ := strconv.Unquote(lit.Value)
+							ranged[v.Name] = append(ranged[v.Name], s)
+						}
+					}
+				}
+			}
+
+			return true
+		})
+
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Get" && len(call.Args) == 1 {
+				if qc, ok := sel.X.(*ast.CallExpr); ok {
+					if qs, ok := qc.Fun.(*ast.SelectorExpr); ok && qs.Sel.Name == "Query" {
+						switch a := call.Args[0].(type) {
+						case *ast.BasicLit:
+							s, _ := strconv.Unquote(a.Value)
+							queries[s] = true
+						case *ast.SelectorExpr:
+							if x, ok := a.X.(*ast.Ident); ok {
+								for _, s := range ranged[x.Name] {
+									queries[s] = true
+								}
+							}
+						}
+					}
+				}
+			}
+
+			for _, arg := range call.Args {
+				if id, ok := arg.(*ast.Ident); !ok || id.Name != "r" {
+					continue
+				}
+
+				switch fn := call.Fun.(type) {
+				case *ast.Ident:
+					collect(fn.Name, queries, visited)
+				case *ast.SelectorExpr:
+					collect(fn.Sel.Name, queries, visited)
+				}
+			}
+
+			return true
+		})
+	}
+
+	handlers := map[string][]string{}
+	ast.Inspect(funcs["setHandlers"], func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "setHandler" || len(call.Args) < 2 {
+			return true
+		}
+
+		pathName, ok := call.Args[0].(*ast.Ident)
+		if !ok {
+			t.Fatalf("path of setHandler is not a variable")
+		}
+
+		h, ok := call.Args[1].(*ast.SelectorExpr)
+		if !ok {
+			t.Fatalf("handler of %s is not a method", pathName.Name)
+		}
+
+		queries := map[string]bool{}
+		collect(h.Sel.Name, queries, map[string]bool{})
+
+		l := []string{}
+		for q := range queries {
+			l = append(l, q)
+		}
+
+		handlers[paths[pathName.Name]] = l
+
+		return true
+	})
+
+	return handlers
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	hd := &Handlers{router: mux.NewRouter(), routes: map[string]*mux.Route{}}
+	hd.setHandlers()
+
+	handlers := openAPIHandlerQueries(t)
+
+	if err := hd.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			t.Errorf("failed to get path template: %v", err)
+
+			return nil
+		}
+
+		spec, found := openAPIRoutes[tpl]
+		if !found {
+			t.Errorf("route, %s not in openAPIRoutes", tpl)
+
+			return nil
+		}
+
+		accepted, found := handlers[tpl]
+		if !found {
+			t.Errorf("handler of route, %s not found", tpl)
+
+			return nil
+		}
+
+		for _, q := range spec.queries {
+			if _, found := openAPIQueries[q]; !found {
+				t.Errorf("route, %s: query %q not in openAPIQueries", tpl, q)
+			}
+		}
+
+		methods, _ := route.GetMethods()
+		for _, m := range methods {
+			if m != http.MethodGet {
+				continue
+			}
+
+			documented := append([]string{}, spec.queries...)
+			sort.Strings(documented)
+			sort.Strings(accepted)
+
+			if strings.Join(documented, ",") != strings.Join(accepted, ",") {
+				t.Errorf("route, %s: documented queries %v, but the handler reads %v", tpl, documented, accepted)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to walk routes: %v", err)
+	}
+
+	for tpl := range openAPIRoutes {
+		if _, found := handlers[tpl]; !found {
+			t.Errorf("route, %s in openAPIRoutes, but not registered", tpl)
+		}
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	cases := []struct {
+		name   string
+		tpl    string
+		path   string
+		params []string
+	}{
+		{name: "no params", tpl: "/contracts", path: "/contracts"},
+		{name: "any", tpl: "/nft/{contract:.*}/count", path: "/nft/{contract}/count", params: []string{"contract"}},
+		{
+			name: "pattern", tpl: `/timestamp/{contract:.*}/project/{project:.+}/id/{tid:[0-9]+}`,
+			path: "/timestamp/{contract}/project/{project}/id/{tid}", params: []string{"contract", "project", "tid"},
+		},
+		{name: "nested braces", tpl: `/a/{id:[0-9]{2,3}}/b`, path: "/a/{id}/b", params: []string{"id"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path, params := openAPIPath(c.tpl)
+			if path != c.path {
+				t.Errorf("expected path %s, but %s", c.path, path)
+			}
+
+			names := make([]string, len(params))
+			for i := range params {
+				names[i] = params[i].(map[string]interface{})["name"].(string)
+			}
+
+			if strings.Join(names, ",") != strings.Join(c.params, ",") {
+				t.Errorf("expected params %v, but %v", c.params, names)
+			}
+		})
+	}
+}
+
+func TestSwaggerUIFiles(t *testing.T) {
+	b, err := swaggerUIFiles.ReadFile("swaggerui/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := regexp.MustCompile(`(?:src|href|url)\s*[=:]\s*"([^"]+)"`).FindAllStringSubmatch(string(b), -1)
+	if len(refs) < 1 {
+		t.Fatal("no references in index.html")
+	}
+
+	for _, ref := range refs {
+		u := ref[1]
+
+		switch {
+		case strings.HasPrefix(u, "https://"):
+			if !strings.Contains(u, "swagger-ui-dist@") {
+				t.Errorf("external asset should be pinned, %q", u)
+			}
+		case strings.Contains(u, "://"):
+			t.Errorf("external asset should be https, %q", u)
+		case "/"+u == HandlerPathOpenAPI:
+		default:
+			if _, err := swaggerUIFiles.Open(path.Join("swaggerui", u)); err != nil {
+				t.Errorf("%q of index.html is not embedded, %v", u, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package digest
+
+import (
+	"bytes"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	currencydigest "github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var rateLimitSweepInterval = time.Minute
+
+// RateLimitRule allows Limit requests in Interval. The tokens are refilled
+// evenly over the interval, so bursts up to Limit are allowed. Limit less
+// than 1 means unlimited.
+type RateLimitRule struct {
+	Limit    int64         `yaml:"limit" json:"limit"`
+	Interval time.Duration `yaml:"interval" json:"interval"`
+}
+
+func (r RateLimitRule) IsValid() error {
+	if r.Limit > 0 && r.Interval <= 0 {
+		return errors.Errorf("invalid rate limit interval, %v", r.Interval)
+	}
+
+	return nil
+}
+
+func (r RateLimitRule) unlimited() bool {
+	return r.Limit < 1
+}
+
+// RateLimitDesign is the rate limit section of digest design,
+//
+//	digest:
+//	  rate_limit:
+//	    default:
+//	      limit: 600
+//	      interval: 1m
+//	    routes:
+//	      /block/{height:[0-9]+}:
+//	        limit: 60
+//	        interval: 1m
+//	    clients:
+//	      10.0.0.0/8:
+//	        limit: 0
+//	      my-api-key:
+//	        limit: 6000
+//	        interval: 1m
+//	    api_key_header: X-API-Key
+//	    trusted_proxies:
+//	      - 127.0.0.1
+//
+// The client is the api key given by api_key_header when the key is one of
+// clients, or else the remote ip; the ip from X-Forwarded-For is used only
+// when the request comes thru the trusted proxies. The rule of clients is used
+// first, the rule of routes next and default last.
+//
+// The rule of clients is the quota of the client over all the routes; the
+// requests of the api key, or of each ip in the network, share one bucket. The
+// rules of routes and default are counted by route and client, so every route
+// has it's own bucket of the client.
+type RateLimitDesign struct {
+	Default        *RateLimitRule           `yaml:"default" json:"default,omitempty"`
+	Routes         map[string]RateLimitRule `yaml:"routes" json:"routes,omitempty"`
+	Clients        map[string]RateLimitRule `yaml:"clients" json:"clients,omitempty"`
+	APIKeyHeader   string                   `yaml:"api_key_header" json:"api_key_header,omitempty"`
+	TrustedProxies []string                 `yaml:"trusted_proxies" json:"trusted_proxies,omitempty"`
+}
+
+// UnmarshalYAML does not allow the unknown fields, so the mistyped rule is not
+// ignored silently.
+func (d *RateLimitDesign) UnmarshalYAML(n *yaml.Node) error {
+	b, err := yaml.Marshal(n)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	type design RateLimitDesign
+
+	var u design
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&u); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*d = RateLimitDesign(u)
+
+	return nil
+}
+
+func (d RateLimitDesign) IsValid() error {
+	if d.Default != nil {
+		if err := d.Default.IsValid(); err != nil {
+			return errors.WithMessage(err, "default")
+		}
+	}
+
+	for k := range d.Routes {
+		if err := d.Routes[k].IsValid(); err != nil {
+			return errors.WithMessagef(err, "route, %q", k)
+		}
+	}
+
+	for k := range d.Clients {
+		if err := d.Clients[k].IsValid(); err != nil {
+			return errors.WithMessagef(err, "client, %q", k)
+		}
+	}
+
+	for i := range d.TrustedProxies {
+		if _, err := parseIPNet(d.TrustedProxies[i]); err != nil {
+			return errors.WithMessage(err, "trusted proxy")
+		}
+	}
+
+	return nil
+}
+
+type rateLimitNet struct {
+	n    *net.IPNet
+	rule RateLimitRule
+}
+
+type rateLimiter struct {
+	design  RateLimitDesign
+	keys    map[string]RateLimitRule
+	nets    []rateLimitNet
+	proxies []*net.IPNet
+	store   *rateLimitStore
+}
+
+func newRateLimiter(design RateLimitDesign) (*rateLimiter, error) {
+	if err := design.IsValid(); err != nil {
+		return nil, err
+	}
+
+	rl := &rateLimiter{design: design, keys: map[string]RateLimitRule{}, store: newRateLimitStore()}
+
+	for k := range design.Clients {
+		if n, err := parseIPNet(k); err == nil {
+			rl.nets = append(rl.nets, rateLimitNet{n: n, rule: design.Clients[k]})
+		} else {
+			rl.keys[k] = design.Clients[k]
+		}
+	}
+
+	for i := range design.TrustedProxies {
+		n, _ := parseIPNet(design.TrustedProxies[i])
+		rl.proxies = append(rl.proxies, n)
+	}
+
+	return rl, nil
+}
+
+func (rl *rateLimiter) attached(prefix string) bool {
+	if _, found := rl.design.Routes[prefix]; found {
+		return true
+	}
+
+	return rl.design.Default != nil || len(rl.design.Clients) > 0
+}
+
+// rule returns the rule of the request; byClient is true when the rule is of
+// clients, which is counted over all the routes.
+func (rl *rateLimiter) rule(prefix, key string, ip net.IP) (_ RateLimitRule, byClient, found bool) {
+	if r, found := rl.keys[key]; found {
+		return r, true, true
+	}
+
+	if ip != nil {
+		for i := range rl.nets {
+			if rl.nets[i].n.Contains(ip) {
+				return rl.nets[i].rule, true, true
+			}
+		}
+	}
+
+	if r, found := rl.design.Routes[prefix]; found {
+		return r, false, true
+	}
+
+	if rl.design.Default != nil {
+		return *rl.design.Default, false, true
+	}
+
+	return RateLimitRule{}, false, false
+}
+
+// client returns the api key or the ip of the request.
+func (rl *rateLimiter) client(r *http.Request) (string, net.IP) {
+	if len(rl.design.APIKeyHeader) > 0 {
+		// NOTE unknown keys are not trusted, or clients can bypass the limit by
+		// changing keys.
+		if key := r.Header.Get(rl.design.APIKeyHeader); len(key) > 0 {
+			if _, found := rl.keys[key]; found {
+				return key, nil
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+
+	if ip != nil && rl.trusted(ip) {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			fip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+			if fip == nil {
+				break
+			}
+
+			ip = fip
+
+			if !rl.trusted(fip) {
+				break
+			}
+		}
+	}
+
+	if ip == nil {
+		return host, nil
+	}
+
+	return ip.String(), ip
+}
+
+func (rl *rateLimiter) trusted(ip net.IP) bool {
+	for i := range rl.proxies {
+		if rl.proxies[i].Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rl *rateLimiter) middleware(prefix string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		var key, id string
+
+		client, ip := rl.client(r)
+		if ip == nil {
+			key = client
+			id = "key:" + client
+		} else {
+			id = "ip:" + client
+		}
+
+		rule, byClient, found := rl.rule(prefix, key, ip)
+		if !found || rule.unlimited() {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		if !byClient {
+			id = prefix + " " + id
+		}
+
+		allowed, remaining, retry, reset := rl.store.take(id, rule, time.Now())
+
+		w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(rule.Limit, 10))
+		w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(ceilSeconds(reset), 10))
+
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(retry), 10))
+			currencydigest.HTTP2ProblemWithError(w, errors.Errorf("too many requests"), http.StatusTooManyRequests)
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// rateLimitStore keeps the token buckets in memory. The buckets which are
+// filled up again are removed periodically.
+type rateLimitStore struct {
+	sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimitStore() *rateLimitStore {
+	return &rateLimitStore{buckets: map[string]*tokenBucket{}, lastSweep: time.Now()}
+}
+
+// take consumes a token of the bucket. retry is the time until the next token
+// and reset is the time until the bucket is full.
+func (s *rateLimitStore) take(key string, rule RateLimitRule, now time.Time) (
+	allowed bool, remaining int64, retry, reset time.Duration,
+) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.sweep(now)
+
+	limit := float64(rule.Limit)
+
+	perToken := rule.Interval / time.Duration(rule.Limit)
+	if perToken < 1 {
+		perToken = 1
+	}
+
+	b, found := s.buckets[key]
+	if !found {
+		b = &tokenBucket{tokens: limit, last: now}
+		s.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(limit, b.tokens+float64(elapsed)/float64(perToken))
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retry = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+
+	reset = time.Duration((limit - b.tokens) * float64(perToken))
+	b.full = now.Add(reset)
+
+	return allowed, int64(b.tokens), retry, reset
+}
+
+func (s *rateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+
+	for k := range s.buckets {
+		if !now.Before(s.buckets[k].full) {
+			delete(s.buckets, k)
+		}
+	}
+
+	s.lastSweep = now
+}
+
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return n, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf("invalid ip, %q", s)
+	}
+
+	bits := 8 * net.IPv6len
+	if i := ip.To4(); i != nil {
+		ip = i
+		bits = 8 * net.IPv4len
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,160 @@
+package digest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRateLimitStoreTake(t *testing.T) {
+	rule := RateLimitRule{Limit: 2, Interval: 2 * time.Second}
+	t0 := time.Unix(1700000000, 0)
+
+	cases := []struct {
+		name      string
+		key       string
+		after     time.Duration
+		allowed   bool
+		remaining int64
+		retry     time.Duration
+		reset     time.Duration
+	}{
+		{name: "first", key: "a", allowed: true, remaining: 1, reset: time.Second},
+		{name: "burst", key: "a", allowed: true, remaining: 0, reset: 2 * time.Second},
+		{name: "empty", key: "a", retry: time.Second, reset: 2 * time.Second},
+		{name: "half refilled", key: "a", after: 500 * time.Millisecond, retry: 500 * time.Millisecond, reset: 1500 * time.Millisecond},
+		{name: "refilled", key: "a", after: time.Second, allowed: true, remaining: 0, reset: 2 * time.Second},
+		{name: "other key", key: "b", after: time.Second, allowed: true, remaining: 1, reset: time.Second},
+		{name: "full again", key: "a", after: 10 * time.Second, allowed: true, remaining: 1, reset: time.Second},
+	}
+
+	s := newRateLimitStore()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			allowed, remaining, retry, reset := s.take(c.key, rule, t0.Add(c.after))
+
+			if allowed != c.allowed {
+				t.Errorf("expected allowed %v, but %v", c.allowed, allowed)
+			}
+
+			if remaining != c.remaining {
+				t.Errorf("expected remaining %d, but %d", c.remaining, remaining)
+			}
+
+			if retry != c.retry {
+				t.Errorf("expected retry %v, but %v", c.retry, retry)
+			}
+
+			if reset != c.reset {
+				t.Errorf("expected reset %v, but %v", c.reset, reset)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rl, err := newRateLimiter(RateLimitDesign{
+		Default:      &RateLimitRule{Limit: 1, Interval: time.Minute},
+		Clients:      map[string]RateLimitRule{"my-api-key": {Limit: 1, Interval: time.Minute}},
+		APIKeyHeader: "X-API-Key",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	handlers := map[string]http.Handler{
+		"/a": rl.middleware("/a", ok),
+		"/b": rl.middleware("/b", ok),
+	}
+
+	cases := []struct {
+		name   string
+		route  string
+		key    string
+		status int
+		retry  string
+	}{
+		{name: "api key", route: "/a", key: "my-api-key", status: http.StatusOK},
+		{name: "api key of other route shares quota", route: "/b", key: "my-api-key", status: http.StatusTooManyRequests, retry: "60"},
+		{name: "ip", route: "/a", status: http.StatusOK},
+		{name: "ip of other route", route: "/b", status: http.StatusOK},
+		{name: "ip again", route: "/a", status: http.StatusTooManyRequests, retry: "60"},
+		{name: "unknown api key is ip", route: "/b", key: "unknown", status: http.StatusTooManyRequests, retry: "60"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, c.route, nil)
+			r.RemoteAddr = "10.0.0.1:3000"
+
+			if len(c.key) > 0 {
+				r.Header.Set("X-API-Key", c.key)
+			}
+
+			w := httptest.NewRecorder()
+			handlers[c.route].ServeHTTP(w, r)
+
+			if w.Code != c.status {
+				t.Errorf("expected status %d, but %d", c.status, w.Code)
+			}
+
+			if s := w.Header().Get("Retry-After"); s != c.retry {
+				t.Errorf("expected Retry-After %q, but %q", c.retry, s)
+			}
+		})
+	}
+}
+
+func TestRateLimitDesignUnmarshalYAML(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		err  bool
+	}{
+		{name: "valid", s: "default:\n  limit: 10\n  interval: 1m\nclients:\n  10.0.0.0/8:\n    limit: 0\n"},
+		{name: "unknown rule field", s: "default:\n  limit: 10\n  intervl: 1m\n", err: true},
+		{name: "unknown field", s: "route:\n  /:\n    limit: 10\n", err: true},
+		{name: "wrong interval", s: "default:\n  limit: 10\n  interval: soon\n", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var m struct {
+				Digest Design `yaml:"digest"`
+			}
+
+			s := "digest:\n  cursor_secret: \"\"\n  rate_limit:\n"
+			for _, l := range strings.Split(strings.TrimSpace(c.s), "\n") {
+				s += "    " + l + "\n"
+			}
+
+			err := yaml.Unmarshal([]byte(s), &m)
+			switch {
+			case c.err && err == nil:
+				t.Fatal("expected error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if m.Digest.RateLimit == nil {
+				t.Fatal("empty rate_limit")
+			}
+
+			if err := m.Digest.IsValid(); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := m.Digest.RateLimit.Default; d == nil || d.Limit != 10 || d.Interval != time.Minute {
+				t.Errorf("unexpected default, %+v", d)
+			}
+		})
+	}
+}